@@ -0,0 +1,302 @@
+package asciitable
+
+import (
+	"fmt"
+	"strings"
+)
+
+const _ansiReset = "\u001b[0m"
+
+// Visible grapheme cluster with all ANSI sequences preceding it
+type ansiCluster struct {
+	escapes string
+	text    string
+}
+
+// Active SGR (Select Graphic Rendition) sequences since the last reset.
+type sgrState []string
+
+// Get parameters of the SGR sequence, or false, if the sequence is not SGR
+func sgrParams(sequence string) (string, bool) {
+	if !strings.HasPrefix(sequence, "\u001b[") || !strings.HasSuffix(sequence, "m") {
+		return "", false
+	}
+	return sequence[2 : len(sequence)-1], true
+}
+
+// Check if parameters of the SGR sequence start with a reset
+func sgrResets(params string) bool {
+	return params == "" || params == "0" || strings.HasPrefix(params, "0;")
+}
+
+// Update state with the ANSI sequences. Non-SGR sequences are ignored.
+func (state *sgrState) apply(table *SimpleTable, escapes string) {
+	for _, sequence := range table.stripAnsiRegex.FindAllString(escapes, -1) {
+		params, ok := sgrParams(sequence)
+		if !ok {
+			continue
+		}
+		if sgrResets(params) {
+			*state = (*state)[:0]
+		}
+		if params != "" && params != "0" {
+			*state = append(*state, sequence)
+		}
+	}
+}
+
+/*
+Sequences to re-open the state at the beginning of a line, followed by
+the escapes of its first cluster. Attributes, which the escapes reset right
+away, are not re-opened, and so is the reset itself.
+*/
+func (state sgrState) open(table *SimpleTable, escapes string) string {
+	sequences := append([]string{}, state...)
+	for _, sequence := range table.stripAnsiRegex.FindAllString(escapes, -1) {
+		if params, ok := sgrParams(sequence); ok && sgrResets(params) {
+			kept := sequences[:0]
+			for _, opened := range sequences {
+				if _, ok := sgrParams(opened); !ok {
+					kept = append(kept, opened)
+				}
+			}
+			sequences = kept
+			if params == "" || params == "0" {
+				continue
+			}
+		}
+		sequences = append(sequences, sequence)
+	}
+	return strings.Join(sequences, "")
+}
+
+// Sequence to close the state at the end of a line
+func (state sgrState) close() string {
+	if len(state) > 0 {
+		return _ansiReset
+	}
+	return ""
+}
+
+// Split data into visible grapheme clusters, keeping ANSI sequences attached
+// to the cluster that follows them. Sequences at the very end are returned separately.
+func (table *SimpleTable) ansiClusters(data string) ([]ansiCluster, string) {
+	clusters := make([]ansiCluster, 0, len(data))
+	var escapes strings.Builder
+	offset := 0
+	for _, loc := range append(table.stripAnsiRegex.FindAllStringIndex(data, -1), []int{len(data), len(data)}) {
+		text := data[offset:loc[0]]
+		for len(text) > 0 {
+			size := table.measure.nextCluster(text)
+			clusters = append(clusters, ansiCluster{escapes: escapes.String(), text: text[:size]})
+			escapes.Reset()
+			text = text[size:]
+		}
+		escapes.WriteString(data[loc[0]:loc[1]])
+		offset = loc[1]
+	}
+
+	return clusters, escapes.String()
+}
+
+// Wrap data by words to the lines, not wider than the width. Text attributes
+// are closed at the end of each wrapped line and re-opened on the next one,
+// so colored text stays colored over all the lines.
+func (table *SimpleTable) wrapAnsi(data string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	clusters, trailing := table.ansiClusters(data)
+
+	// Group clusters into words. Escapes found in whitespace go to the next word.
+	words := make([][]ansiCluster, 0)
+	var word []ansiCluster
+	var spaceEscapes string
+	for _, cluster := range clusters {
+		if strings.TrimSpace(cluster.text) == "" {
+			if len(word) > 0 {
+				words = append(words, word)
+				word = nil
+			}
+			spaceEscapes += cluster.escapes
+			continue
+		}
+		cluster.escapes = spaceEscapes + cluster.escapes
+		spaceEscapes = ""
+		word = append(word, cluster)
+	}
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+	trailing = spaceEscapes + trailing
+
+	lines := make([]string, 0)
+	state := make(sgrState, 0)
+	var line [][]ansiCluster
+	lineWidth := 0
+
+	flush := func(last bool) {
+		var rendered strings.Builder
+		opened := false
+		for idx, lineWord := range line {
+			if idx > 0 {
+				rendered.WriteString(" ")
+			}
+			for _, cluster := range lineWord {
+				escapes := cluster.escapes
+				if !opened {
+					escapes, opened = state.open(table, escapes), true
+				}
+				state.apply(table, cluster.escapes)
+				rendered.WriteString(escapes + cluster.text)
+			}
+		}
+		if last {
+			escapes := trailing
+			if !opened {
+				escapes = state.open(table, escapes)
+			}
+			state.apply(table, trailing)
+			rendered.WriteString(escapes)
+		}
+		rendered.WriteString(state.close())
+		lines = append(lines, rendered.String())
+		line, lineWidth = nil, 0
+	}
+
+	for _, word := range words {
+		wordWidth := table.clustersWidth(word)
+		if lineWidth > 0 && lineWidth+1+wordWidth <= width {
+			line = append(line, word)
+			lineWidth += 1 + wordWidth
+			continue
+		}
+
+		if lineWidth > 0 {
+			flush(false)
+		}
+
+		// Split the word, which is too long for a single line
+		for wordWidth > width {
+			chunkWidth, split := 0, 0
+			for split < len(word) && chunkWidth+table.measure.clusterWidth(word[split].text) <= width {
+				chunkWidth += table.measure.clusterWidth(word[split].text)
+				split++
+			}
+			if split == 0 {
+				split = 1 // Wide character on a single-cell line
+			}
+			if split == len(word) {
+				break
+			}
+			line = [][]ansiCluster{word[:split]}
+			flush(false)
+			word = word[split:]
+			wordWidth = table.clustersWidth(word)
+		}
+		line = [][]ansiCluster{word}
+		lineWidth = wordWidth
+	}
+	flush(true)
+
+	return lines
+}
+
+// Get display width of the clusters
+func (table *SimpleTable) clustersWidth(clusters []ansiCluster) int {
+	width := 0
+	for _, cluster := range clusters {
+		width += table.measure.clusterWidth(cluster.text)
+	}
+	return width
+}
+
+// Truncate data to the width, counting only visible characters. Escape sequences
+// and grapheme clusters are never split, text attributes are reset before the
+// ellipsis. Position of the cut and the ellipsis are configured in the table.
+func (table *SimpleTable) truncateAnsi(data string, width int) string {
+	clusters, trailing := table.ansiClusters(data)
+	if table.clustersWidth(clusters) <= width {
+		return data
+	}
+
+	ellipsis, ellipsisWidth := table.ellipsis, table.textWidth(table.ellipsis)
+	if ellipsisWidth > width {
+		ellipsis, ellipsisWidth = "", 0
+	}
+
+	// Split the room between beginning and ending of the data
+	headWidth, tailWidth := 0, 0
+	switch table.truncatePosition {
+	case TRUNCATE_START:
+		tailWidth = width - ellipsisWidth
+	case TRUNCATE_MIDDLE:
+		tailWidth = (width - ellipsisWidth) / 2
+		headWidth = width - ellipsisWidth - tailWidth
+	default:
+		headWidth = width - ellipsisWidth
+	}
+
+	head, used := 0, 0
+	for head < len(clusters) && used+table.measure.clusterWidth(clusters[head].text) <= headWidth {
+		used += table.measure.clusterWidth(clusters[head].text)
+		head++
+	}
+	tail, used := len(clusters), 0
+	for tail > head && used+table.measure.clusterWidth(clusters[tail-1].text) <= tailWidth {
+		used += table.measure.clusterWidth(clusters[tail-1].text)
+		tail--
+	}
+
+	var truncated strings.Builder
+	state := make(sgrState, 0)
+	for _, cluster := range clusters[:head] {
+		state.apply(table, cluster.escapes)
+		truncated.WriteString(cluster.escapes + cluster.text)
+	}
+	truncated.WriteString(state.close())
+	truncated.WriteString(ellipsis)
+
+	// Attributes of the cut-out part still apply to the ending
+	for _, cluster := range clusters[head:tail] {
+		state.apply(table, cluster.escapes)
+	}
+	if tail < len(clusters) {
+		for idx, cluster := range clusters[tail:] {
+			escapes := cluster.escapes
+			if idx == 0 {
+				escapes = state.open(table, escapes)
+			}
+			state.apply(table, cluster.escapes)
+			truncated.WriteString(escapes + cluster.text)
+		}
+		state.apply(table, trailing)
+		truncated.WriteString(trailing)
+		truncated.WriteString(state.close())
+	}
+
+	return truncated.String()
+}
+
+// Basic 16 colors, as in xterm
+var _ansiPalette = []string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// Get hex RGB value of the ANSI 256-colors palette entry
+func ansiColorHex(color int) string {
+	switch {
+	case color < 0 || color > 255:
+		return ""
+	case color < 16:
+		return _ansiPalette[color]
+	case color < 232: // 6x6x6 color cube
+		levels := []int{0, 95, 135, 175, 215, 255}
+		color -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[color/36], levels[color/6%6], levels[color%6])
+	}
+	gray := 8 + (color-232)*10
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+}
@@ -0,0 +1,65 @@
+package asciitable
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapAnsi(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		width int
+		lines []string
+	}{
+		{"empty", "", 5, []string{""}},
+		{"words", "one two three", 7, []string{"one two", "three"}},
+		{"zero width", "a b", 0, []string{"a", "b"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"wide characters", "日本語テキスト", 5, []string{"日本", "語テ", "キス", "ト"}},
+		{"color reopened", "\x1b[31mred error message\x1b[0m", 9, []string{"\x1b[31mred error\x1b[0m", "\x1b[31mmessage\x1b[0m"}},
+		{"color of the long word", "\x1b[32mabcdef\x1b[0m", 4, []string{"\x1b[32mabcd\x1b[0m", "\x1b[32mef\x1b[0m"}},
+		{"reset on the next line", "\x1b[1mbold \x1b[32mgreen\x1b[0m plain text", 10, []string{"\x1b[1mbold \x1b[32mgreen\x1b[0m", "plain text"}},
+		{"reset keeps later colors", "\x1b[1mbold words\x1b[0;31m red", 5, []string{"\x1b[1mbold\x1b[0m", "\x1b[1mwords\x1b[0m", "\x1b[0;31mred\x1b[0m"}},
+	}
+	table := NewSimpleTable(nil, nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if lines := table.wrapAnsi(test.data, test.width); !reflect.DeepEqual(lines, test.lines) {
+				t.Errorf("wrapAnsi(%q, %d) = %q, expected %q", test.data, test.width, lines, test.lines)
+			}
+		})
+	}
+}
+
+func TestTruncateAnsi(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		ellipsis string
+		data     string
+		width    int
+		expected string
+	}{
+		{"fits", TRUNCATE_END, "...", "short", 10, "short"},
+		{"end", TRUNCATE_END, "...", "Strawberry jam", 8, "Straw..."},
+		{"start", TRUNCATE_START, "...", "Strawberry jam", 8, "...y jam"},
+		{"middle", TRUNCATE_MIDDLE, "...", "Strawberry jam", 8, "Str...am"},
+		{"custom ellipsis", TRUNCATE_MIDDLE, "…", "Strawberry jam", 8, "Stra…jam"},
+		{"no room for ellipsis", TRUNCATE_END, "...", "abcdef", 2, "ab"},
+		{"wide characters", TRUNCATE_END, "...", "日本語テキスト", 7, "日本..."},
+		{"wide characters at start", TRUNCATE_START, "...", "日本語テキスト", 7, "...スト"},
+		{"color end", TRUNCATE_END, "...", "\x1b[31mred error message\x1b[0m", 8, "\x1b[31mred e\x1b[0m..."},
+		{"color start", TRUNCATE_START, "...", "\x1b[31mred error message\x1b[0m", 8, "...\x1b[31mssage\x1b[0m"},
+		{"reset at the cut", TRUNCATE_START, "…", "\x1b[31mabc\x1b[0mde", 3, "…de"},
+		{"color middle", TRUNCATE_MIDDLE, "...", "\x1b[31mred error message\x1b[0m", 8, "\x1b[31mred\x1b[0m...\x1b[31mge\x1b[0m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewSimpleTable(nil, nil).SetTruncatePosition(test.position).SetEllipsis(test.ellipsis)
+			if truncated := table.truncateAnsi(test.data, test.width); truncated != test.expected {
+				t.Errorf("truncateAnsi(%q, %d) = %q, expected %q", test.data, test.width, truncated, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package asciitable
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Color output modes
+const (
+	COLOR_AUTO   = iota // Colors only on terminals, honoring NO_COLOR, FORCE_COLOR and TERM=dumb
+	COLOR_ALWAYS        // Colors and escape sequences are always written
+	COLOR_NEVER         // All escape sequences are stripped
+)
+
+// Border glyph sets
+const (
+	GLYPHS_AUTO    = iota // Unicode, if the locale and terminal can display it, otherwise ASCII
+	GLYPHS_UNICODE        // Glyphs as they are defined
+	GLYPHS_ASCII          // Glyphs are mapped to the closest ASCII ones
+)
+
+// Box-drawing glyphs to weights of their lines: up, right, down, left
+var _junctionArms = make(map[string][4]int)
+
+func init() {
+	for arms, glyph := range _junctions {
+		_junctionArms[glyph] = arms
+	}
+}
+
+/*
+Check if colors should be written in COLOR_AUTO mode to the output, which is
+a terminal or not. NO_COLOR disables them, FORCE_COLOR enables them, unless it
+is "0" or "false". Otherwise colors are enabled only on terminals, except the dumb one.
+*/
+func colorSupported(terminal bool) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(strings.TrimSpace(force)) {
+		case "0", "false", "no", "off":
+			return false
+		}
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return terminal
+}
+
+// Check if the output is a terminal
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+/*
+Check if the locale and terminal are able to display Unicode box-drawing glyphs.
+The first set of LC_ALL, LC_CTYPE and LANG must be UTF-8. Dumb and VT terminals,
+typical on serial consoles, are ASCII-only. Windows consoles usually do not set
+the locale, but display Unicode fine.
+*/
+func unicodeSupported() bool {
+	term := strings.ToLower(os.Getenv("TERM"))
+	if term == "dumb" || strings.HasPrefix(term, "vt") {
+		return false
+	}
+
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := strings.ToLower(os.Getenv(name)); locale != "" {
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+
+	return runtime.GOOS == "windows"
+}
+
+// Get the closest ASCII glyph of the box-drawing one. Heavy horizontal lines
+// become "=", just as in ASCII styles, unknown glyphs become "+".
+func asciiGlyph(glyph string) string {
+	isASCII := true
+	for _, r := range glyph {
+		if r > 0x7f {
+			isASCII = false
+			break
+		}
+	}
+	if isASCII {
+		return glyph
+	}
+
+	switch glyph {
+	case "┄", "┅", "┈", "┉", "╌", "╍": // Dashed horizontal
+		return "-"
+	case "┆", "┇", "┊", "┋", "╎", "╏": // Dashed vertical
+		return "|"
+	}
+
+	arms, ok := _junctionArms[glyph]
+	switch {
+	case !ok:
+		return "+"
+	case arms[0] == _lineNone && arms[2] == _lineNone:
+		if arms[1] == _lineThick || arms[1] == _lineDouble || arms[3] == _lineThick || arms[3] == _lineDouble {
+			return "="
+		}
+		return "-"
+	case arms[1] == _lineNone && arms[3] == _lineNone:
+		return "|"
+	}
+	return "+"
+}
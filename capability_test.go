@@ -0,0 +1,133 @@
+package asciitable
+
+import (
+	"os"
+	"testing"
+)
+
+// Set the environment variables for the test, unsetting the other ones of the names
+func setenv(t *testing.T, env map[string]string, names ...string) {
+	for _, name := range names {
+		t.Setenv(name, "") // Restores the variable after the test
+		if value, ok := env[name]; ok {
+			os.Setenv(name, value)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestColorSupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		terminal bool
+		expected bool
+	}{
+		{"terminal", nil, true, true},
+		{"not a terminal", nil, false, false},
+		{"no color", map[string]string{"NO_COLOR": "1"}, true, false},
+		{"no color wins over force", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, true, false},
+		{"force", map[string]string{"FORCE_COLOR": "1"}, false, true},
+		{"force empty", map[string]string{"FORCE_COLOR": ""}, false, true},
+		{"force off", map[string]string{"FORCE_COLOR": "0"}, true, false},
+		{"force false", map[string]string{"FORCE_COLOR": " False "}, true, false},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, true, false},
+		{"dumb terminal forced", map[string]string{"TERM": "dumb", "FORCE_COLOR": "1"}, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setenv(t, test.env, "NO_COLOR", "FORCE_COLOR", "TERM")
+			if supported := colorSupported(test.terminal); supported != test.expected {
+				t.Errorf("colorSupported(%v) = %v, expected %v", test.terminal, supported, test.expected)
+			}
+		})
+	}
+}
+
+func TestUnicodeSupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{"utf-8", map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{"utf8", map[string]string{"LANG": "de_DE.utf8"}, true},
+		{"posix", map[string]string{"LANG": "C"}, false},
+		{"lc_all first", map[string]string{"LC_ALL": "C", "LANG": "C.UTF-8"}, false},
+		{"lc_ctype before lang", map[string]string{"LC_CTYPE": "C.UTF-8", "LANG": "C"}, true},
+		{"dumb terminal", map[string]string{"TERM": "dumb", "LANG": "C.UTF-8"}, false},
+		{"vt terminal", map[string]string{"TERM": "vt100", "LANG": "C.UTF-8"}, false},
+		{"xterm", map[string]string{"TERM": "xterm-256color", "LANG": "C.UTF-8"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setenv(t, test.env, "LC_ALL", "LC_CTYPE", "LANG", "TERM")
+			if supported := unicodeSupported(); supported != test.expected {
+				t.Errorf("unicodeSupported() = %v, expected %v", supported, test.expected)
+			}
+		})
+	}
+}
+
+func TestAsciiGlyph(t *testing.T) {
+	tests := []struct {
+		glyph    string
+		expected string
+	}{
+		{"+", "+"},
+		{"─", "-"},
+		{"━", "="},
+		{"═", "="},
+		{"│", "|"},
+		{"┆", "|"},
+		{"┄", "-"},
+		{"┼", "+"},
+		{"╔", "+"},
+		{"★", "+"},
+	}
+	for _, test := range tests {
+		if glyph := asciiGlyph(test.glyph); glyph != test.expected {
+			t.Errorf("asciiGlyph(%q) = %q, expected %q", test.glyph, glyph, test.expected)
+		}
+	}
+}
+
+func TestRenderAsciiFallback(t *testing.T) {
+	data := NewTableData().SetHeader("A", "B").AddRow(1, 2)
+	tests := []struct {
+		name     string
+		locale   string
+		style    func() *BorderStyle
+		expected string
+	}{
+		{"unicode locale", "C.UTF-8", func() *BorderStyle { return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN) },
+			"\n┌─┬─┐\n│A│B│\n│1│2│\n└─┴─┘"},
+		{"posix locale", "C", func() *BorderStyle { return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN) },
+			"\n+-+-+\n|A|B|\n|1|2|\n+-+-+"},
+		{"double lines", "C", func() *BorderStyle { return NewBorderStyle(BORDER_DOUBLE, BORDER_DOUBLE) },
+			"\n+=+=+\n|A|B|\n|1|2|\n+=+=+"},
+		{"forced unicode", "C", func() *BorderStyle {
+			return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)
+		}, "\n┌─┬─┐\n│A│B│\n│1│2│\n└─┴─┘"},
+		{"forced ascii", "C.UTF-8", func() *BorderStyle {
+			return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_ASCII)
+		}, "\n+-+-+\n|A|B|\n|1|2|\n+-+-+"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Style is built in the other locale, glyphs are resolved at render time
+			other := map[string]string{"LANG": "C.UTF-8"}
+			if test.locale == "C.UTF-8" {
+				other["LANG"] = "C"
+			}
+			setenv(t, other, "LC_ALL", "LC_CTYPE", "LANG", "TERM")
+			style := test.style()
+
+			setenv(t, map[string]string{"LANG": test.locale}, "LANG")
+			if rendered := NewSimpleTable(data, style).SetColorMode(COLOR_NEVER).Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package asciitable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Basic ANSI colors, in order of their SGR codes
+var _colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+/*
+TextStyle defines colors and attributes of text, emitted as SGR sequences
+at render time. Colors are names (black, red, green, yellow, blue, magenta,
+cyan, white, optionally with "bright-" prefix), 256-color palette indexes
+from "0" to "255", or RGB values as "#rrggbb". Empty color is the default one.
+*/
+type TextStyle struct {
+	Foreground string `json:"fg,omitempty"`
+	Background string `json:"bg,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+}
+
+// Get SGR parameters of the color. Base is 30 for foreground and 40 for background.
+func colorParams(color string, base int) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(color))
+	bright := strings.HasPrefix(name, "bright-")
+	if bright {
+		name = strings.TrimPrefix(name, "bright-")
+	}
+	for idx, colorName := range _colorNames {
+		if name == colorName {
+			if bright {
+				return strconv.Itoa(base + 60 + idx), nil
+			}
+			return strconv.Itoa(base + idx), nil
+		}
+	}
+
+	if index, err := strconv.Atoi(name); err == nil && !bright && index >= 0 && index < 256 {
+		return fmt.Sprintf("%d;5;%d", base+8, index), nil
+	}
+	if len(name) == 7 && name[0] == '#' && !bright {
+		if rgb, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return fmt.Sprintf("%d;2;%d;%d;%d", base+8, rgb>>16, (rgb>>8)&0xff, rgb&0xff), nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown color %q: %w", color, ErrInvalidOption)
+}
+
+// Get SGR sequence of the style, or empty string, if the style is empty.
+// Errors name the invalid field, e.g. "fg: unknown color".
+func (textStyle TextStyle) sgr() (string, error) {
+	params := make([]string, 0, 5)
+	if textStyle.Bold {
+		params = append(params, "1")
+	}
+	if textStyle.Italic {
+		params = append(params, "3")
+	}
+	if textStyle.Underline {
+		params = append(params, "4")
+	}
+	if textStyle.Foreground != "" {
+		param, err := colorParams(textStyle.Foreground, 30)
+		if err != nil {
+			return "", fmt.Errorf("fg: %w", err)
+		}
+		params = append(params, param)
+	}
+	if textStyle.Background != "" {
+		param, err := colorParams(textStyle.Background, 40)
+		if err != nil {
+			return "", fmt.Errorf("bg: %w", err)
+		}
+		params = append(params, param)
+	}
+
+	if len(params) == 0 {
+		return "", nil
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m", nil
+}
+
+// Apply SGR sequence to the text. Resets inside the text, e.g. from colored
+// cell data, restore the sequence, so the text is styled until its end.
+func paintText(sgr string, text string) string {
+	if sgr == "" || text == "" {
+		return text
+	}
+	text = strings.ReplaceAll(strings.TrimSuffix(text, _ansiReset), _ansiReset, _ansiReset+sgr)
+	text = strings.ReplaceAll(text, "\x1b[m", "\x1b[m"+sgr)
+	return sgr + text + _ansiReset
+}
@@ -0,0 +1,128 @@
+package asciitable
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextStyleSGR(t *testing.T) {
+	tests := []struct {
+		name      string
+		textStyle TextStyle
+		expected  string
+	}{
+		{"empty", TextStyle{}, ""},
+		{"name", TextStyle{Foreground: "red"}, "\x1b[31m"},
+		{"case and spaces", TextStyle{Foreground: " White "}, "\x1b[37m"},
+		{"background", TextStyle{Background: "black"}, "\x1b[40m"},
+		{"bright", TextStyle{Foreground: "bright-green", Background: "bright-blue"}, "\x1b[92;104m"},
+		{"palette", TextStyle{Foreground: "0", Background: "255"}, "\x1b[38;5;0;48;5;255m"},
+		{"rgb", TextStyle{Foreground: "#FF8000", Background: "#000001"}, "\x1b[38;2;255;128;0;48;2;0;0;1m"},
+		{"attributes", TextStyle{Bold: true, Italic: true, Underline: true}, "\x1b[1;3;4m"},
+		{"attributes and colors", TextStyle{Foreground: "cyan", Background: "magenta", Bold: true}, "\x1b[1;36;45m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sgr, err := test.textStyle.sgr()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sgr != test.expected {
+				t.Errorf("sgr() = %q, expected %q", sgr, test.expected)
+			}
+		})
+	}
+}
+
+func TestTextStyleErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		textStyle TextStyle
+		message   string
+	}{
+		{"unknown name", TextStyle{Foreground: "purple"}, `fg: unknown color "purple"`},
+		{"bright palette", TextStyle{Foreground: "bright-1"}, `fg: unknown color "bright-1"`},
+		{"palette out of range", TextStyle{Background: "256"}, `bg: unknown color "256"`},
+		{"negative palette", TextStyle{Background: "-1"}, `bg: unknown color "-1"`},
+		{"short rgb", TextStyle{Foreground: "#fff"}, `fg: unknown color "#fff"`},
+		{"invalid rgb", TextStyle{Background: "#gg0000"}, `bg: unknown color "#gg0000"`},
+		{"bright rgb", TextStyle{Foreground: "bright-#ffffff"}, `fg: unknown color "bright-#ffffff"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.textStyle.sgr()
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("sgr() = %v, expected %v", err, ErrInvalidOption)
+			}
+			if !strings.HasPrefix(err.Error(), test.message) {
+				t.Errorf("sgr() = %q, expected %q", err, test.message)
+			}
+		})
+	}
+
+	table := NewSimpleTable(NewTableData().AddRow("alpha"), nil).SetColTextStyle(TextStyle{Foreground: "purple"}, 0)
+	if err := table.Validate(); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Validate() = %v, expected %v", err, ErrInvalidOption)
+	}
+	style := NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetBorderTextStyle(TextStyle{Background: "#12"})
+	if err := style.Validate(); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Validate() = %v, expected %v", err, ErrInvalidOption)
+	}
+}
+
+func TestPaintText(t *testing.T) {
+	tests := []struct {
+		name     string
+		sgr      string
+		text     string
+		expected string
+	}{
+		{"no style", "", "\x1b[31mred\x1b[0m", "\x1b[31mred\x1b[0m"},
+		{"no text", "\x1b[1m", "", ""},
+		{"plain", "\x1b[1m", "bold", "\x1b[1mbold\x1b[0m"},
+		{"inner reset", "\x1b[1m", "a\x1b[31mb\x1b[0mc", "\x1b[1ma\x1b[31mb\x1b[0m\x1b[1mc\x1b[0m"},
+		{"short reset", "\x1b[1m", "a\x1b[31mb\x1b[mc", "\x1b[1ma\x1b[31mb\x1b[m\x1b[1mc\x1b[0m"},
+		{"trailing reset", "\x1b[1m", "\x1b[31mred\x1b[0m", "\x1b[1m\x1b[31mred\x1b[0m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if painted := paintText(test.sgr, test.text); painted != test.expected {
+				t.Errorf("paintText(%q, %q) = %q, expected %q", test.sgr, test.text, painted, test.expected)
+			}
+		})
+	}
+}
+
+func TestRenderColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     int
+		expected string
+	}{
+		{"always", COLOR_ALWAYS, "\n\x1b[34m+-----+-----+\x1b[0m\n" +
+			"\x1b[34m|\x1b[0m\x1b[1mHost \x1b[0m\x1b[34m|\x1b[0m\x1b[1mState\x1b[0m\x1b[34m|\x1b[0m\n" +
+			"\x1b[34m|\x1b[0m\x1b[33malpha\x1b[0m\x1b[34m|\x1b[0m\x1b[32mok\x1b[0m   \x1b[0m\x1b[34m|\x1b[0m\n" +
+			"\x1b[34m+-----+-----+\x1b[0m\n" +
+			"\x1b[34m|\x1b[0m\x1b[33mbeta \x1b[0m\x1b[34m|\x1b[0m\x1b[41mdown \x1b[0m\x1b[34m|\x1b[0m\n" +
+			"\x1b[34m+-----+-----+\x1b[0m"},
+		{"never", COLOR_NEVER, "\n+-----+-----+\n|Host |State|\n|alpha|ok   |\n+-----+-----+\n|beta |down |\n+-----+-----+"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			style := NewBorderStyle(BORDER_ASCII, BORDER_ASCII).
+				SetBorderTextStyle(TextStyle{Foreground: "blue"}).SetHeaderTextStyle(TextStyle{Bold: true})
+			data := NewTableData().SetHeader("Host", "State").AddRow("alpha", "\x1b[32mok\x1b[0m").AddRow("beta", "down")
+			table := NewSimpleTable(data, style).SetColorMode(test.mode).
+				SetColTextStyle(TextStyle{Foreground: "yellow"}, 0).SetCellTextStyle(TextStyle{Background: "red"}, 1, 1)
+			if rendered := table.Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+
+			var written strings.Builder
+			if err := table.RenderTo(&written); err != nil || written.String() != test.expected {
+				t.Errorf("RenderTo() = %q, %v, expected %q", written.String(), err, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package asciitable
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Quotes handling of CSV fields
+const (
+	CSV_QUOTES_STRICT = iota // RFC 4180 quoting
+	CSV_QUOTES_LAZY          // Quotes may appear in unquoted fields, quoted fields may have bare quotes
+	CSV_QUOTES_NONE          // Quotes are regular characters, as usual in TSV
+)
+
+// Whitespace trimming of CSV fields
+const (
+	CSV_TRIM_NONE = iota
+	CSV_TRIM_LEADING
+	CSV_TRIM_BOTH
+)
+
+// CSVError is returned on malformed input, pointing to the place of the problem.
+type CSVError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (err *CSVError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", err.Line, err.Column, err.Err)
+}
+
+func (err *CSVError) Unwrap() error {
+	return err.Err
+}
+
+// CSVOptions are CSV and TSV format options of import and export.
+type CSVOptions struct {
+	delimiter rune
+	comment   rune
+	quotes    int
+	trim      int
+	header    bool
+	footer    bool
+	escape    bool
+	stripAnsi bool
+	err       error
+}
+
+/*
+NewCSVOptions object constructor. Defaults are comma-separated fields
+with RFC 4180 quotes, no trimming, the first row used as a header,
+the footer written as the last row, no backslash escapes and ANSI
+sequences stripped on export.
+*/
+func NewCSVOptions() *CSVOptions {
+	options := new(CSVOptions)
+	options.delimiter = ','
+	options.quotes = CSV_QUOTES_STRICT
+	options.trim = CSV_TRIM_NONE
+	options.header = true
+	options.footer = true
+	options.stripAnsi = true
+
+	return options
+}
+
+/*
+NewTSVOptions object constructor. Defaults are tab-separated fields
+without quoting, no trimming, the first row used as a header,
+the footer written as the last row, no backslash escapes and ANSI
+sequences stripped on export.
+*/
+func NewTSVOptions() *CSVOptions {
+	return NewCSVOptions().SetDelimiter('\t').SetQuotes(CSV_QUOTES_NONE)
+}
+
+// Set fields delimiter
+func (options *CSVOptions) SetDelimiter(delimiter rune) *CSVOptions {
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || !utf8.ValidRune(delimiter) {
+		options.setError(fmt.Errorf("SetDelimiter: invalid delimiter %q: %w", delimiter, ErrInvalidOption))
+		return options
+	}
+	options.delimiter = delimiter
+	return options
+}
+
+// Set comment character. Lines, starting with it, are skipped. Zero disables comments.
+func (options *CSVOptions) SetComment(comment rune) *CSVOptions {
+	options.comment = comment
+	return options
+}
+
+// Set quotes handling: CSV_QUOTES_STRICT, CSV_QUOTES_LAZY or CSV_QUOTES_NONE
+func (options *CSVOptions) SetQuotes(quotes int) *CSVOptions {
+	if quotes != CSV_QUOTES_STRICT && quotes != CSV_QUOTES_LAZY && quotes != CSV_QUOTES_NONE {
+		options.setError(fmt.Errorf("SetQuotes: unknown mode %d: %w", quotes, ErrInvalidOption))
+		return options
+	}
+	options.quotes = quotes
+	return options
+}
+
+// Set whitespace trimming of fields: CSV_TRIM_NONE, CSV_TRIM_LEADING or CSV_TRIM_BOTH
+func (options *CSVOptions) SetTrim(trim int) *CSVOptions {
+	if trim != CSV_TRIM_NONE && trim != CSV_TRIM_LEADING && trim != CSV_TRIM_BOTH {
+		options.setError(fmt.Errorf("SetTrim: unknown mode %d: %w", trim, ErrInvalidOption))
+		return options
+	}
+	options.trim = trim
+	return options
+}
+
+// Set if the first row is a header
+func (options *CSVOptions) SetHeader(header bool) *CSVOptions {
+	options.header = header
+	return options
+}
+
+// Set if the footer, with computed aggregates, is written as the last row on export.
+// Import has no footer, so the row is read back as data.
+func (options *CSVOptions) SetFooter(footer bool) *CSVOptions {
+	options.footer = footer
+	return options
+}
+
+/*
+Set if fields without quoting (CSV_QUOTES_NONE) have backslash escapes.
+Export escapes backslashes, tabs, line breaks and delimiters, e.g. tab
+becomes \t, and import decodes them back. Otherwise backslashes are regular
+characters, and fields with delimiters or line breaks can not be exported.
+*/
+func (options *CSVOptions) SetEscape(escape bool) *CSVOptions {
+	options.escape = escape
+	return options
+}
+
+// Set if ANSI sequences are stripped from the fields on export
+func (options *CSVOptions) SetStripAnsi(strip bool) *CSVOptions {
+	options.stripAnsi = strip
+	return options
+}
+
+// Record the error, unless there is one already
+func (options *CSVOptions) setError(err error) {
+	if options.err == nil {
+		options.err = err
+	}
+}
+
+// Validate returns the first error, occurred while options were configured.
+// With escapes and without quoting the delimiter can not be a backslash or a letter of escapes.
+func (options *CSVOptions) Validate() error {
+	if options.err == nil && options.escape && options.quotes == CSV_QUOTES_NONE && strings.ContainsRune(`\tnr`, options.delimiter) {
+		return fmt.Errorf("delimiter %q can not be escaped: %w", options.delimiter, ErrInvalidOption)
+	}
+	return options.err
+}
+
+// Trim field according to the options
+func (options *CSVOptions) trimField(field string) string {
+	switch options.trim {
+	case CSV_TRIM_LEADING:
+		return strings.TrimLeft(field, " \t")
+	case CSV_TRIM_BOTH:
+		return strings.TrimSpace(field)
+	}
+	return field
+}
+
+/*
+NewTableDataFromCSV constructs table data from CSV input. If options are nil,
+defaults of NewCSVOptions are used. All rows must have the same number of fields.
+Without quoting (CSV_QUOTES_NONE) fields are read literally, unless escapes
+are enabled: then backslash escapes, written by WriteCSV, are decoded, e.g.
+\t becomes tab. Unknown escapes are kept as they are.
+*/
+func NewTableDataFromCSV(reader io.Reader, options *CSVOptions) (*TableData, error) {
+	if options == nil {
+		options = NewCSVOptions()
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	tableData := NewTableData()
+	var err error
+	if options.quotes == CSV_QUOTES_NONE {
+		err = tableData.readDelimited(reader, options)
+	} else {
+		err = tableData.readCSV(reader, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tableData, nil
+}
+
+/*
+NewTableDataFromTSV constructs table data from TSV input. If options are nil,
+defaults of NewTSVOptions are used.
+*/
+func NewTableDataFromTSV(reader io.Reader, options *CSVOptions) (*TableData, error) {
+	if options == nil {
+		options = NewTSVOptions()
+	}
+	return NewTableDataFromCSV(reader, options)
+}
+
+// Add parsed record either as a header or as a data row
+func (tableData *TableData) addRecord(record []string, options *CSVOptions) {
+	for idx, field := range record {
+		record[idx] = options.trimField(field)
+	}
+	if options.header && tableData.header == nil {
+		tableData.SetHeader(record...)
+	} else {
+		tableData.appendRow(record)
+	}
+}
+
+// Read RFC 4180 input
+func (tableData *TableData) readCSV(reader io.Reader, options *CSVOptions) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = options.delimiter
+	csvReader.Comment = options.comment
+	csvReader.LazyQuotes = options.quotes == CSV_QUOTES_LAZY
+	csvReader.TrimLeadingSpace = options.trim != CSV_TRIM_NONE
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				// Report the first field out of the expected range, or the last one, if fields are missing
+				field := len(record) - 1
+				if csvReader.FieldsPerRecord < len(record) {
+					field = csvReader.FieldsPerRecord
+				}
+				line, column := csvReader.FieldPos(field)
+				return &CSVError{Line: line, Column: column, Err: csv.ErrFieldCount}
+			}
+			return &CSVError{Line: parseErr.Line, Column: parseErr.Column, Err: parseErr.Err}
+		} else if err != nil {
+			return err
+		}
+
+		tableData.addRecord(record, options)
+	}
+}
+
+// Read input, where fields are split by the delimiter, which is not escaped, without any quoting
+func (tableData *TableData) readDelimited(reader io.Reader, options *CSVOptions) error {
+	buff := bufio.NewReader(reader)
+	fields := -1
+	for lineNum := 1; ; lineNum++ {
+		line, err := buff.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if err == io.EOF && line == "" {
+			return nil
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" || (options.comment != 0 && strings.HasPrefix(line, string(options.comment))) {
+			continue
+		}
+
+		record, starts := options.splitFields(line)
+		if fields < 0 {
+			fields = len(record)
+		} else if len(record) != fields {
+			column := len(line) + 1
+			if len(record) > fields {
+				column = starts[fields] + 1
+			}
+			return &CSVError{Line: lineNum, Column: column, Err: csv.ErrFieldCount}
+		}
+
+		tableData.addRecord(record, options)
+	}
+}
+
+/*
+WriteCSV writes table data as CSV to the writer, row by row. If options
+are nil, defaults of NewCSVOptions are used. Header and footer are written
+only if the options have them enabled. Without quoting (CSV_QUOTES_NONE)
+fields with delimiters or line breaks are written only with escapes enabled,
+otherwise an error wrapping ErrInvalidOption is returned.
+*/
+func (tableData *TableData) WriteCSV(writer io.Writer, options *CSVOptions) error {
+	if options == nil {
+		options = NewCSVOptions()
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	var write func(record []string) error
+	var flush func() error
+	if options.quotes == CSV_QUOTES_NONE {
+		buff := bufio.NewWriter(writer)
+		delimiter := string(options.delimiter)
+		write = func(record []string) error {
+			fields := make([]string, len(record))
+			for idx, field := range record {
+				if options.escape {
+					field = options.escapeField(field)
+				} else if strings.ContainsAny(field, delimiter+"\r\n") {
+					return fmt.Errorf("field %q can not be written without quotes or escapes: %w", field, ErrInvalidOption)
+				}
+				fields[idx] = field
+			}
+			_, err := buff.WriteString(strings.Join(fields, delimiter) + "\n")
+			return err
+		}
+		flush = buff.Flush
+	} else {
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Comma = options.delimiter
+		write = csvWriter.Write
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	}
+
+	ansiRegex := regexp.MustCompile(_ansiRegex)
+	writeRecord := func(record []string) error {
+		if options.stripAnsi {
+			stripped := make([]string, len(record))
+			for idx, field := range record {
+				stripped[idx] = ansiRegex.ReplaceAllString(field, "")
+			}
+			record = stripped
+		}
+		return write(record)
+	}
+
+	if options.header && len(tableData.header) > 0 {
+		if err := writeRecord(tableData.header); err != nil {
+			return err
+		}
+	}
+	for _, row := range tableData.data {
+		if err := writeRecord(row); err != nil {
+			return err
+		}
+	}
+	if options.footer && tableData.HasFooter() {
+		if err := writeRecord(tableData.GetFooter()); err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+/*
+WriteTSV writes table data as TSV to the writer, row by row. If options
+are nil, defaults of NewTSVOptions are used.
+*/
+func (tableData *TableData) WriteTSV(writer io.Writer, options *CSVOptions) error {
+	if options == nil {
+		options = NewTSVOptions()
+	}
+	return tableData.WriteCSV(writer, options)
+}
+
+// Escape field, which can not be quoted
+func (options *CSVOptions) escapeField(field string) string {
+	field = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\n", "\\n", "\r", "\\r").Replace(field)
+	if options.delimiter != '\t' {
+		field = strings.ReplaceAll(field, string(options.delimiter), "\\"+string(options.delimiter))
+	}
+	return field
+}
+
+// Split line by the delimiters, which are not escaped, and decode escapes
+// of escapeField, if they are enabled. Returns fields and their byte offsets in the line.
+func (options *CSVOptions) splitFields(line string) ([]string, []int) {
+	fields, starts := make([]string, 0), []int{0}
+	var field strings.Builder
+	for offset := 0; offset < len(line); {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+		switch {
+		case r == options.delimiter:
+			fields = append(fields, field.String())
+			starts = append(starts, offset)
+			field.Reset()
+		case r == '\\' && options.escape && offset < len(line):
+			escaped, size := utf8.DecodeRuneInString(line[offset:])
+			offset += size
+			switch escaped {
+			case 't':
+				field.WriteRune('\t')
+			case 'n':
+				field.WriteRune('\n')
+			case 'r':
+				field.WriteRune('\r')
+			case '\\', options.delimiter:
+				field.WriteRune(escaped)
+			default:
+				field.WriteRune(r)
+				field.WriteRune(escaped)
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	return append(fields, field.String()), starts
+}
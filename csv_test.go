@@ -0,0 +1,195 @@
+package asciitable
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewTableDataFromCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options *CSVOptions
+		header  []string
+		data    [][]string
+	}{
+		{"defaults", "a,b\n1,2\n", nil, []string{"a", "b"}, [][]string{{"1", "2"}}},
+		{"quoted", "a,b\n\"x, y\",\"say \"\"hi\"\"\"\n", nil, []string{"a", "b"}, [][]string{{"x, y", "say \"hi\""}}},
+		{"multiline", "a,b\n\"x\ny\",2\n", nil, []string{"a", "b"}, [][]string{{"x\ny", "2"}}},
+		{"lazy quotes", "a,b\nx\"y,2\n", NewCSVOptions().SetQuotes(CSV_QUOTES_LAZY), []string{"a", "b"}, [][]string{{"x\"y", "2"}}},
+		{"delimiter and trim", "a;b\n 1; 2 \n", NewCSVOptions().SetDelimiter(';').SetTrim(CSV_TRIM_BOTH), []string{"a", "b"}, [][]string{{"1", "2"}}},
+		{"trim leading", "a,b\n 1, 2 \n", NewCSVOptions().SetTrim(CSV_TRIM_LEADING), []string{"a", "b"}, [][]string{{"1", "2 "}}},
+		{"comments", "# hosts\na,b\n1,2\n", NewCSVOptions().SetComment('#'), []string{"a", "b"}, [][]string{{"1", "2"}}},
+		{"no header", "1,2\n3,4\n", NewCSVOptions().SetHeader(false), nil, [][]string{{"1", "2"}, {"3", "4"}}},
+		{"tsv", "a\tb\n1\t\"2\"\n", NewTSVOptions(), []string{"a", "b"}, [][]string{{"1", "\"2\""}}},
+		{"tsv crlf", "a\tb\r\n1\t2\r\n", NewTSVOptions(), []string{"a", "b"}, [][]string{{"1", "2"}}},
+		{"tsv comments", "# hosts\na\tb\n\n1\t2\n", NewTSVOptions().SetComment('#'), []string{"a", "b"}, [][]string{{"1", "2"}}},
+		{"tsv backslashes", "Path\tNote\nC:\\temp\\new\ta\\tb\\\n", NewTSVOptions(), []string{"Path", "Note"}, [][]string{{`C:\temp\new`, `a\tb\`}}},
+		{"tsv escapes", "Path\tNote\nC:\\\\temp\ta\\tb\\\tc\\q\n", NewTSVOptions().SetEscape(true), []string{"Path", "Note"}, [][]string{{`C:\temp`, "a\tb\tc\\q"}}},
+		{"csv ignores escapes", "a,b\nx\\ty,2\n", NewCSVOptions().SetEscape(true), []string{"a", "b"}, [][]string{{`x\ty`, "2"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableData, err := NewTableDataFromCSV(strings.NewReader(test.input), test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header := *tableData.GetHeader(); !reflect.DeepEqual(header, test.header) {
+				t.Errorf("header = %q, expected %q", header, test.header)
+			}
+			if data := *tableData.GetData(); !reflect.DeepEqual(data, test.data) {
+				t.Errorf("data = %q, expected %q", data, test.data)
+			}
+		})
+	}
+}
+
+func TestNewTableDataFromCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options *CSVOptions
+		line    int
+		column  int
+		err     error
+	}{
+		{"extra field", "a,b\n1,2,3\n", nil, 2, 5, csv.ErrFieldCount},
+		{"missing field", "a,b\n1\n", nil, 2, 1, csv.ErrFieldCount},
+		{"bare quote", "a,b\nx\"y,2\n", nil, 2, 2, csv.ErrBareQuote},
+		{"tsv extra field", "a\tb\n1\t2\t3\n", NewTSVOptions(), 2, 5, csv.ErrFieldCount},
+		{"tsv missing field", "a\tb\n\n1\n", NewTSVOptions(), 3, 2, csv.ErrFieldCount},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTableDataFromCSV(strings.NewReader(test.input), test.options)
+			var csvErr *CSVError
+			if !errors.As(err, &csvErr) {
+				t.Fatalf("error = %v, expected CSVError", err)
+			}
+			if csvErr.Line != test.line || csvErr.Column != test.column || !errors.Is(err, test.err) {
+				t.Errorf("error = %v, expected line %d, column %d: %v", err, test.line, test.column, test.err)
+			}
+		})
+	}
+}
+
+func TestCSVOptionsErrors(t *testing.T) {
+	for name, options := range map[string]*CSVOptions{
+		"delimiter": NewCSVOptions().SetDelimiter('"'),
+		"quotes":    NewCSVOptions().SetQuotes(42),
+		"trim":      NewCSVOptions().SetTrim(42),
+	} {
+		if _, err := NewTableDataFromCSV(strings.NewReader("a\n"), options); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: error = %v, expected %v", name, err, ErrInvalidOption)
+		}
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func() *CSVOptions
+	}{
+		{"csv", NewCSVOptions},
+		{"csv semicolon", func() *CSVOptions { return NewCSVOptions().SetDelimiter(';') }},
+		{"tsv with escapes", func() *CSVOptions { return NewTSVOptions().SetEscape(true) }},
+		{"semicolon with escapes", func() *CSVOptions {
+			return NewCSVOptions().SetDelimiter(';').SetQuotes(CSV_QUOTES_NONE).SetEscape(true)
+		}},
+		{"comma with escapes", func() *CSVOptions { return NewCSVOptions().SetQuotes(CSV_QUOTES_NONE).SetEscape(true) }},
+	}
+	header := []string{"Name", "Path; or, tab"}
+	data := [][]string{
+		{"tab\there", `C:\temp\new`},
+		{"a;b", "a,b"},
+		{"line\nbreak", "cr\rlf"},
+		{`say "hi"`, `trailing\`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableData := NewTableData().SetHeader(header...)
+			for _, row := range data {
+				tableData.AddRow(row[0], row[1])
+			}
+
+			var output strings.Builder
+			if err := tableData.WriteCSV(&output, test.options()); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := NewTableDataFromCSV(strings.NewReader(output.String()), test.options())
+			if err != nil {
+				t.Fatalf("%v, output:\n%s", err, output.String())
+			}
+			if !reflect.DeepEqual(*loaded.GetHeader(), header) {
+				t.Errorf("header = %q, expected %q", *loaded.GetHeader(), header)
+			}
+			if !reflect.DeepEqual(*loaded.GetData(), data) {
+				t.Errorf("data = %q, expected %q, output:\n%s", *loaded.GetData(), data, output.String())
+			}
+		})
+	}
+}
+
+func TestWriteCSVUnescapableDelimiter(t *testing.T) {
+	err := NewTableData().SetHeader("a").WriteCSV(&strings.Builder{}, NewTSVOptions().SetDelimiter('\\').SetEscape(true))
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("error = %v, expected %v", err, ErrInvalidOption)
+	}
+	if err := NewTableData().SetHeader("a").WriteCSV(&strings.Builder{}, NewTSVOptions().SetDelimiter('\\')); err != nil {
+		t.Errorf("error = %v without escapes", err)
+	}
+}
+
+func TestWriteCSVWithoutEscapes(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		err   error
+	}{
+		{"backslash", `C:\temp\new`, nil},
+		{"quote", `say "hi"`, nil},
+		{"tab", "tab\there", ErrInvalidOption},
+		{"newline", "line\nbreak", ErrInvalidOption},
+		{"carriage return", "cr\rlf", ErrInvalidOption},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output strings.Builder
+			err := NewTableData().SetHeader("Path").AddRow(test.field).WriteTSV(&output, nil)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, expected %v", err, test.err)
+			}
+			if expected := "Path\n" + test.field + "\n"; err == nil && output.String() != expected {
+				t.Errorf("WriteTSV() = %q, expected %q", output.String(), expected)
+			}
+		})
+	}
+}
+
+func TestWriteCSVFooter(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *CSVOptions
+		expected string
+	}{
+		{"csv", nil, "Host,Size\nalpha,10\nbeta,32\nTotal,42\n"},
+		{"tsv", NewTSVOptions(), "Host\tSize\nalpha\t10\nbeta\t32\nTotal\t42\n"},
+		{"without footer", NewCSVOptions().SetFooter(false), "Host,Size\nalpha,10\nbeta,32\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableData := NewTableData().SetHeader("Host", "Size").AddRow("alpha", 10).AddRow("beta", 32).
+				SetFooter("Total").SetFooterAggregate(AGGREGATE_SUM, 1)
+			var output strings.Builder
+			if err := tableData.WriteCSV(&output, test.options); err != nil {
+				t.Fatal(err)
+			}
+			if output.String() != test.expected {
+				t.Errorf("WriteCSV() = %q, expected %q", output.String(), test.expected)
+			}
+		})
+	}
+}
@@ -7,8 +7,20 @@ import (
 )
 
 type TableData struct {
-	header []string
-	data   [][]string
+	header          []string
+	data            [][]string
+	align           []int // Preferred columns align, e.g. from struct tags
+	width           []int // Preferred columns width, zero is automatic
+	footer          []string
+	groups          [][]HeaderGroup         // Header group levels from the top one
+	aggregates      map[int]footerAggregate // Aggregates of the footer by column, -1 is for all
+	spans           map[[2]int][2]int       // Spans of cells by row and column: rows and columns
+	merges          map[int]bool            // Columns, where repeated values are merged, -1 is for all
+	cellSpans       map[[2]int][2]int       // Set spans and spans of merged values, computed from data
+	origins         map[[2]int][2]int       // Cells, covering positions, computed from cell spans
+	originsRevision uint64
+	revision        uint64 // Incremented on each change, so renderers know their cached layout is stale
+	err             error  // First error of data update
 }
 
 /*
@@ -20,9 +32,14 @@ func NewTableData() *TableData {
 	return tableData
 }
 
+/*
+Add rows of data. Data should be two-dimentional array with at least one row,
+containing at least one element, otherwise ErrNoData is reported by Err.
+*/
 func (tableData *TableData) SetData(data [][]interface{}) *TableData {
-	if len(data) < 1 && len(data[0]) < 1 {
-		panic("ERROR: Data should to be two-dimentional array with at least one row, containing at least one element.")
+	if len(data) < 1 || len(data[0]) < 1 {
+		tableData.setError(fmt.Errorf("SetData: %w", ErrNoData))
+		return tableData
 	}
 
 	for _, row := range data {
@@ -36,13 +53,17 @@ func (tableData *TableData) AddRow(row ...interface{}) *TableData {
 	data := make([]string, len(row))
 	for idx, rowData := range row {
 		var cellData string
+		if rowData == nil {
+			data[idx] = ""
+			continue
+		}
 		switch reflect.TypeOf(rowData).Kind() {
 		case reflect.Int:
 			cellData = fmt.Sprintf("%d", rowData)
 		case reflect.Slice, reflect.Array, reflect.Map:
 			cellData = fmt.Sprintf("*### Error: %s ###*", reflect.TypeOf(rowData))
 		case reflect.String:
-			cellData = strings.TrimSpace(rowData.(string))
+			cellData = strings.TrimSpace(reflect.ValueOf(rowData).String())
 		default:
 			cellData = fmt.Sprintf("%v", rowData)
 		}
@@ -51,11 +72,52 @@ func (tableData *TableData) AddRow(row ...interface{}) *TableData {
 
 	if len(row) > 0 {
 		tableData.data = append(tableData.data, data)
+		tableData.revision++
 	}
 
 	return tableData
 }
 
+// Record the error, unless there is one already
+func (tableData *TableData) setError(err error) {
+	if tableData.err == nil {
+		tableData.err = err
+	}
+}
+
+// Err returns the first error, occurred while data was updated.
+func (tableData *TableData) Err() error {
+	return tableData.err
+}
+
+// Append already formatted row as is
+func (tableData *TableData) appendRow(row []string) {
+	if len(row) > 0 {
+		tableData.data = append(tableData.data, row)
+		tableData.revision++
+	}
+}
+
+// Get preferred align of the column. Default is left.
+func (tableData *TableData) getColAlignHint(column int) int {
+	if column < len(tableData.align) {
+		return tableData.align[column]
+	}
+	return ALIGN_LEFT
+}
+
+// Get preferred column widths, or no widths at all, if none is set.
+func (tableData *TableData) getColWidthHints() []int {
+	for _, width := range tableData.width {
+		if width > 0 {
+			widths := make([]int, len(tableData.width))
+			copy(widths, tableData.width)
+			return widths
+		}
+	}
+	return make([]int, 0)
+}
+
 // Get raw table data
 func (tableData *TableData) GetData() *[][]string {
 	return &tableData.data
@@ -87,6 +149,7 @@ Previous data is wept away.
 func (tableData *TableData) SetHeader(titles ...string) *TableData {
 	tableData.header = make([]string, len(titles))
 	copy(tableData.header, titles)
+	tableData.revision++
 
 	return tableData
 }
@@ -0,0 +1,16 @@
+package asciitable
+
+import (
+	"errors"
+)
+
+// Errors, reported by Validate, Render and other calls. Returned errors wrap
+// these, so they can be checked with errors.Is.
+var (
+	ErrNoData           = errors.New("no header or data has been set")
+	ErrColumnOutOfRange = errors.New("column does not exist")
+	ErrRowOutOfRange    = errors.New("row does not exist")
+	ErrInvalidOption    = errors.New("invalid option value")
+	ErrTerminalSize     = errors.New("terminal size is not available")
+	ErrUnknownStyle     = errors.New("style is not registered")
+)
@@ -0,0 +1,199 @@
+package asciitable
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Footer aggregates, computed from numeric cells of the column at render time.
+const (
+	AGGREGATE_SUM = iota
+	AGGREGATE_COUNT
+	AGGREGATE_AVG
+	AGGREGATE_MIN
+	AGGREGATE_MAX
+)
+
+// AggregateFunc computes footer cell from numeric values of the column.
+type AggregateFunc func(values []float64) string
+
+// Aggregate of the footer cell, either built-in or custom one
+type footerAggregate struct {
+	aggregate int
+	custom    AggregateFunc
+}
+
+var _numberAnsiRegex = regexp.MustCompile(_ansiRegex)
+
+/*
+Set literal cells of the footer, e.g. "Total". Cells with aggregates
+are computed instead. Footer is rendered under the data rows.
+*/
+func (tableData *TableData) SetFooter(cells ...string) *TableData {
+	tableData.footer = make([]string, len(cells))
+	copy(tableData.footer, cells)
+	tableData.revision++
+
+	return tableData
+}
+
+/*
+Set aggregate of the footer cells: AGGREGATE_SUM, AGGREGATE_COUNT, AGGREGATE_AVG,
+AGGREGATE_MIN or AGGREGATE_MAX. Cells, which are not numbers, are skipped.
+If columns contains only one value and it is -1, then aggregate applies
+to all columns without own one.
+*/
+func (tableData *TableData) SetFooterAggregate(aggregate int, columns ...int) *TableData {
+	if aggregate < AGGREGATE_SUM || aggregate > AGGREGATE_MAX {
+		tableData.setError(fmt.Errorf("SetFooterAggregate: unknown aggregate %d: %w", aggregate, ErrInvalidOption))
+		return tableData
+	}
+	return tableData.setFooterAggregate("SetFooterAggregate", footerAggregate{aggregate: aggregate}, columns)
+}
+
+// Set custom aggregate of the footer cells. Function gets numeric values of the column.
+func (tableData *TableData) SetFooterFunc(aggregate AggregateFunc, columns ...int) *TableData {
+	if aggregate == nil {
+		tableData.setError(fmt.Errorf("SetFooterFunc: function is required: %w", ErrInvalidOption))
+		return tableData
+	}
+	return tableData.setFooterAggregate("SetFooterFunc", footerAggregate{custom: aggregate}, columns)
+}
+
+// Set aggregate of the columns, -1 is for all
+func (tableData *TableData) setFooterAggregate(caller string, aggregate footerAggregate, columns []int) *TableData {
+	for _, column := range columns {
+		if column < 0 && !(column == -1 && len(columns) == 1) {
+			tableData.setError(fmt.Errorf("%s: column %d: %w", caller, column, ErrColumnOutOfRange))
+			return tableData
+		}
+	}
+
+	if tableData.aggregates == nil {
+		tableData.aggregates = make(map[int]footerAggregate)
+	}
+	for _, column := range columns {
+		tableData.aggregates[column] = aggregate
+	}
+	tableData.revision++
+
+	return tableData
+}
+
+// Check if the footer is set
+func (tableData *TableData) HasFooter() bool {
+	return len(tableData.footer) > 0 || len(tableData.aggregates) > 0
+}
+
+/*
+Get footer cells, with aggregates computed from the current data.
+Footer is as long as the header or the longest row, unless literal
+cells or aggregates are set beyond. Nil is returned, if there is no footer.
+*/
+func (tableData *TableData) GetFooter() []string {
+	if !tableData.HasFooter() {
+		return nil
+	}
+
+	cols := len(tableData.header)
+	for _, row := range tableData.data {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	if len(tableData.footer) > cols {
+		cols = len(tableData.footer)
+	}
+	for column := range tableData.aggregates {
+		if column >= cols {
+			cols = column + 1
+		}
+	}
+
+	footer := make([]string, cols)
+	copy(footer, tableData.footer)
+	for column := range footer {
+		values, decimals := tableData.columnValues(column)
+		aggregate, ok := tableData.aggregates[column]
+		if !ok {
+			// Aggregate for all columns keeps literal cells and skips text columns
+			aggregate, ok = tableData.aggregates[-1]
+			if !ok || footer[column] != "" || len(values) == 0 {
+				continue
+			}
+		}
+		footer[column] = aggregate.compute(values, decimals)
+	}
+
+	return footer
+}
+
+// Get numeric values of the column and the max number of their decimals
+func (tableData *TableData) columnValues(column int) ([]float64, int) {
+	values := make([]float64, 0, len(tableData.data))
+	decimals := 0
+	for _, row := range tableData.data {
+		if column >= len(row) {
+			continue
+		}
+		cell := strings.TrimSpace(_numberAnsiRegex.ReplaceAllString(row[column], ""))
+		value, err := strconv.ParseFloat(cell, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		values = append(values, value)
+		if dot := strings.IndexByte(cell, '.'); dot > -1 && !strings.ContainsAny(cell, "eE") && len(cell)-dot-1 > decimals {
+			decimals = len(cell) - dot - 1
+		}
+	}
+
+	return values, decimals
+}
+
+// Compute aggregate of the values. Results of built-in aggregates have
+// as many decimals, as the values do, so e.g. money has two decimals.
+func (aggregate footerAggregate) compute(values []float64, decimals int) string {
+	if aggregate.custom != nil {
+		return aggregate.custom(values)
+	}
+	if aggregate.aggregate == AGGREGATE_COUNT {
+		return strconv.Itoa(len(values))
+	}
+	if len(values) == 0 {
+		if aggregate.aggregate == AGGREGATE_SUM {
+			return "0"
+		}
+		return ""
+	}
+
+	result := values[0]
+	switch aggregate.aggregate {
+	case AGGREGATE_SUM, AGGREGATE_AVG:
+		for _, value := range values[1:] {
+			result += value
+		}
+		if aggregate.aggregate == AGGREGATE_AVG {
+			result /= float64(len(values))
+			if decimals < 2 {
+				decimals = 2
+			}
+		}
+	case AGGREGATE_MIN:
+		for _, value := range values[1:] {
+			if value < result {
+				result = value
+			}
+		}
+	case AGGREGATE_MAX:
+		for _, value := range values[1:] {
+			if value > result {
+				result = value
+			}
+		}
+	}
+
+	return strconv.FormatFloat(result, 'f', decimals, 64)
+}
@@ -0,0 +1,47 @@
+package asciitable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetFooter(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("Host", "Size", "Load").
+			AddRow("alpha", "10.50", "1").
+			AddRow("beta", "\x1b[31m2.25\x1b[0m", "n/a").
+			AddRow("gamma", "7", "3")
+	}
+	tests := []struct {
+		name     string
+		data     *TableData
+		expected []string
+	}{
+		{"no footer", data(), nil},
+		{"literal", data().SetFooter("Total"), []string{"Total", "", ""}},
+		{"sum", data().SetFooterAggregate(AGGREGATE_SUM, 1), []string{"", "19.75", ""}},
+		{"count", data().SetFooterAggregate(AGGREGATE_COUNT, 2), []string{"", "", "2"}},
+		{"avg", data().SetFooterAggregate(AGGREGATE_AVG, 2), []string{"", "", "2.00"}},
+		{"min", data().SetFooterAggregate(AGGREGATE_MIN, 1), []string{"", "2.25", ""}},
+		{"max", data().SetFooterAggregate(AGGREGATE_MAX, 1), []string{"", "10.50", ""}},
+		{"all columns", data().SetFooter("Total").SetFooterAggregate(AGGREGATE_SUM, -1), []string{"Total", "19.75", "4"}},
+		{"custom", data().SetFooterFunc(func(values []float64) string { return strings.Repeat("*", len(values)) }, 1), []string{"", "***", ""}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if footer := test.data.GetFooter(); !reflect.DeepEqual(footer, test.expected) {
+				t.Errorf("GetFooter() = %q, expected %q", footer, test.expected)
+			}
+		})
+	}
+}
+
+func TestRenderFooterWithoutRows(t *testing.T) {
+	data := NewTableData().SetHeader("Host", "Size").SetFooter("Total number of hosts").SetFooterAggregate(AGGREGATE_COUNT, 1)
+	expected := "\n┌─────────┬────────┐\n│Host     │Size    │\n│Total ...│0       │\n└─────────┴────────┘"
+	table := NewSimpleTable(data, NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)).SetWidth(20).SetColorMode(COLOR_NEVER)
+	if rendered := table.Render(); rendered != expected {
+		t.Errorf("Render() = %q, expected %q", rendered, expected)
+	}
+}
@@ -0,0 +1,152 @@
+package asciitable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rows of header group levels go from the top one: -4, -5 and so on. Internal use.
+const _rowGroups = -4
+
+// HeaderGroup is a title over the number of columns in a header group level.
+type HeaderGroup struct {
+	Title   string
+	Columns int
+}
+
+// Get row of the header group level
+func groupRow(level int) int {
+	return _rowGroups - level
+}
+
+// Get header group level of the row, or -1, if the row is not a level
+func groupLevel(row int) int {
+	if row > _rowGroups {
+		return -1
+	}
+	return _rowGroups - row
+}
+
+/*
+Add level of header groups, e.g. "Memory" over "Used", "Free" and "Total"
+columns. Levels go from the top one down to the header, groups go from
+the first column. Columns after the last group have empty titles.
+*/
+func (tableData *TableData) AddHeaderGroups(groups ...HeaderGroup) *TableData {
+	for _, group := range groups {
+		if group.Columns < 1 {
+			tableData.setError(fmt.Errorf("AddHeaderGroups: group %q has %d columns: %w", group.Title, group.Columns, ErrInvalidOption))
+			return tableData
+		}
+	}
+
+	level := make([]HeaderGroup, len(groups))
+	copy(level, groups)
+	tableData.groups = append(tableData.groups, level)
+
+	// Groups are cells, which span their columns
+	if tableData.spans == nil {
+		tableData.spans = make(map[[2]int][2]int)
+	}
+	column := 0
+	for _, group := range level {
+		if group.Columns > 1 {
+			tableData.spans[[2]int{groupRow(len(tableData.groups) - 1), column}] = [2]int{1, group.Columns}
+		}
+		column += group.Columns
+	}
+	tableData.revision++
+
+	return tableData
+}
+
+// Get header group levels from the top one
+func (tableData *TableData) GetHeaderGroups() [][]HeaderGroup {
+	return tableData.groups
+}
+
+// Get cells of the header group level. Titles are in the first columns of their groups.
+func (tableData *TableData) groupCells(level int) []string {
+	cells := make([]string, 0)
+	for _, group := range tableData.groups[level] {
+		cells = append(cells, group.Title)
+		cells = append(cells, make([]string, group.Columns-1)...)
+	}
+	return cells
+}
+
+// Get header with titles of the groups over each column, e.g. "Memory Used"
+func (tableData *TableData) flatHeader() []string {
+	if len(tableData.groups) == 0 {
+		return tableData.header
+	}
+
+	header := make([]string, len(tableData.header))
+	for column, title := range tableData.header {
+		titles := make([]string, 0, len(tableData.groups)+1)
+		for level := range tableData.groups {
+			origin := tableData.cellOrigin(groupRow(level), column)
+			if cells := tableData.groupCells(level); origin[1] < len(cells) && cells[origin[1]] != "" {
+				titles = append(titles, cells[origin[1]])
+			}
+		}
+		if title != "" {
+			titles = append(titles, title)
+		}
+		header[column] = strings.Join(titles, " ")
+	}
+	return header
+}
+
+/*
+Set align of the header group levels, which is ALIGN_CENTER by default.
+If levels contains only one value and it is -1, then align applies to all levels at once.
+*/
+func (table *SimpleTable) SetHeaderGroupAlign(align int, levels ...int) *SimpleTable {
+	if err := setGroupsAlign(table.groupsAlign, len(table.Data().GetHeaderGroups()), align, levels); err != nil {
+		table.setError(fmt.Errorf("SetHeaderGroupAlign: %w", err))
+	}
+	return table
+}
+
+// Get align of the cell. Header groups have their own align.
+func (table *SimpleTable) cellAlign(row int, column int) int {
+	if level := groupLevel(row); level > -1 {
+		return groupAlign(table.groupsAlign, level)
+	}
+	return table.columnsAlign[column]
+}
+
+// Set align of the levels in the map of aligns, -1 being all levels
+func setGroupsAlign(aligns map[int]int, levelsNum int, align int, levels []int) error {
+	if align != ALIGN_LEFT && align != ALIGN_RIGHT && align != ALIGN_CENTER {
+		return fmt.Errorf("unknown align %d: %w", align, ErrInvalidOption)
+	}
+
+	if len(levels) == 1 && levels[0] == -1 {
+		for level := range aligns {
+			delete(aligns, level)
+		}
+		aligns[-1] = align
+		return nil
+	}
+	for _, level := range levels {
+		if level < 0 || level >= levelsNum {
+			return fmt.Errorf("level %d: %w", level, ErrRowOutOfRange)
+		}
+	}
+	for _, level := range levels {
+		aligns[level] = align
+	}
+	return nil
+}
+
+// Get align of the level from the map of aligns
+func groupAlign(aligns map[int]int, level int) int {
+	if align, ok := aligns[level]; ok {
+		return align
+	} else if align, ok := aligns[-1]; ok {
+		return align
+	}
+	return ALIGN_CENTER
+}
@@ -0,0 +1,478 @@
+package asciitable
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// How column align is expressed in HTML
+const (
+	HTML_ALIGN_STYLE = iota // style="text-align: right"
+	HTML_ALIGN_CLASS        // class="align-right"
+)
+
+// HTMLTable renders table data as an HTML table.
+type HTMLTable struct {
+	rowsData       *TableData
+	columnsAlign   []int
+	columnsClass   []string
+	groupsAlign    map[int]int
+	rowsClass      map[int]string
+	rowClass       string
+	tableClass     string
+	alignMode      int
+	ansiColors     bool
+	stripAnsiRegex *regexp.Regexp
+	err            error
+}
+
+/*
+NewHTMLTable object constructor
+*/
+func NewHTMLTable(data *TableData) *HTMLTable {
+	table := new(HTMLTable)
+	if data == nil {
+		data = NewTableData()
+	}
+	table.rowsData = data
+
+	table.columnsAlign = make([]int, data.GetColsNum())
+	table.columnsClass = make([]string, len(table.columnsAlign))
+	for idx := range table.columnsAlign {
+		table.columnsAlign[idx] = data.getColAlignHint(idx)
+	}
+	table.groupsAlign = make(map[int]int)
+	table.rowsClass = make(map[int]string)
+	table.alignMode = HTML_ALIGN_STYLE
+	table.ansiColors = false
+	table.stripAnsiRegex = regexp.MustCompile(_ansiRegex)
+
+	return table
+}
+
+// HTML returns HTML table over the same data with the same columns align.
+func (table *SimpleTable) HTML() *HTMLTable {
+	markup := NewHTMLTable(table.Data())
+	markup.columnsAlign = make([]int, len(table.columnsAlign))
+	copy(markup.columnsAlign, table.columnsAlign)
+	markup.columnsClass = make([]string, len(table.columnsAlign))
+	for level, align := range table.groupsAlign {
+		markup.groupsAlign[level] = align
+	}
+
+	return markup
+}
+
+// Data might be added after the table was created
+func (table *HTMLTable) fitColumns() {
+	for len(table.columnsAlign) < table.getColsNum() {
+		table.columnsAlign = append(table.columnsAlign, table.Data().getColAlignHint(len(table.columnsAlign)))
+		table.columnsClass = append(table.columnsClass, "")
+	}
+}
+
+// Set column align
+func (table *HTMLTable) SetColAlign(align int, columns ...int) *HTMLTable {
+	if align != ALIGN_LEFT && align != ALIGN_RIGHT && align != ALIGN_CENTER {
+		table.setError(fmt.Errorf("SetColAlign: unknown align %d: %w", align, ErrInvalidOption))
+		return table
+	}
+	table.fitColumns()
+
+	// Set align to all cells
+	if len(columns) == 1 && columns[0] == -1 {
+		for idx := range table.columnsAlign {
+			table.columnsAlign[idx] = align
+		}
+	} else {
+		// Set only specific cells
+		for _, column := range columns {
+			if column >= 0 && column < len(table.columnsAlign) {
+				table.columnsAlign[column] = align
+			} else {
+				table.setError(fmt.Errorf("SetColAlign: column %d: %w", column, ErrColumnOutOfRange))
+			}
+		}
+	}
+
+	return table
+}
+
+/*
+Set align of the header group levels, which is ALIGN_CENTER by default.
+If levels contains only one value and it is -1, then align applies to all levels at once.
+*/
+func (table *HTMLTable) SetHeaderGroupAlign(align int, levels ...int) *HTMLTable {
+	if err := setGroupsAlign(table.groupsAlign, len(table.Data().GetHeaderGroups()), align, levels); err != nil {
+		table.setError(fmt.Errorf("SetHeaderGroupAlign: %w", err))
+	}
+	return table
+}
+
+// Set how column align is expressed: HTML_ALIGN_STYLE (inline style, default)
+// or HTML_ALIGN_CLASS ("align-left", "align-center" or "align-right" class).
+func (table *HTMLTable) SetAlignMode(mode int) *HTMLTable {
+	if mode != HTML_ALIGN_STYLE && mode != HTML_ALIGN_CLASS {
+		table.setError(fmt.Errorf("SetAlignMode: unknown mode %d: %w", mode, ErrInvalidOption))
+		return table
+	}
+	table.alignMode = mode
+	return table
+}
+
+// Set CSS class of the table element
+func (table *HTMLTable) SetTableClass(class string) *HTMLTable {
+	table.tableClass = class
+	return table
+}
+
+// Set CSS class of the data rows. If rows contains only one value and it is -1,
+// then class applies to all rows at once.
+func (table *HTMLTable) SetRowClass(class string, rows ...int) *HTMLTable {
+	if len(rows) == 1 && rows[0] == -1 {
+		table.rowClass = class
+	} else {
+		for _, row := range rows {
+			if row >= 0 && row < table.Data().GetRowsNum() {
+				table.rowsClass[row] = class
+			} else {
+				table.setError(fmt.Errorf("SetRowClass: row %d: %w", row, ErrRowOutOfRange))
+			}
+		}
+	}
+	return table
+}
+
+// Set CSS class of the column cells, including header
+func (table *HTMLTable) SetColClass(class string, columns ...int) *HTMLTable {
+	table.fitColumns()
+	if len(columns) == 1 && columns[0] == -1 {
+		for idx := range table.columnsClass {
+			table.columnsClass[idx] = class
+		}
+	} else {
+		for _, column := range columns {
+			if column >= 0 && column < len(table.columnsClass) {
+				table.columnsClass[column] = class
+			} else {
+				table.setError(fmt.Errorf("SetColClass: column %d: %w", column, ErrColumnOutOfRange))
+			}
+		}
+	}
+	return table
+}
+
+// Convert ANSI colors and text attributes into styled spans, instead of
+// stripping them out.
+func (table *HTMLTable) SetAnsiColors(convert bool) *HTMLTable {
+	table.ansiColors = convert
+	return table
+}
+
+// Record the error, unless there is one already
+func (table *HTMLTable) setError(err error) {
+	if table.err == nil {
+		table.err = err
+	}
+}
+
+// Validate returns the first error, occurred while the table was configured,
+// or an error of the table data.
+func (table *HTMLTable) Validate() error {
+	if table.err != nil {
+		return table.err
+	}
+	if err := table.Data().Err(); err != nil {
+		return err
+	}
+	return table.Data().validateSpans(table.getColsNum())
+}
+
+// Returns table data
+func (table *HTMLTable) Data() *TableData {
+	return table.rowsData
+}
+
+// Renders table as a string. Empty string is returned, if the table is not valid.
+func (table *HTMLTable) Render() string {
+	var rendered strings.Builder
+	table.render(&rendered) // strings.Builder never returns write errors
+	return rendered.String()
+}
+
+// RenderTo writes the table to the writer, row by row.
+func (table *HTMLTable) RenderTo(writer io.Writer) error {
+	buff := bufio.NewWriter(writer)
+	if err := table.render(buff); err != nil {
+		return err
+	}
+	return buff.Flush()
+}
+
+// Get number of columns. Header and rows might be of a different length.
+func (table *HTMLTable) getColsNum() int {
+	cols := len(*table.Data().GetHeader())
+	for _, row := range *table.Data().GetData() {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	if footer := table.Data().GetFooter(); len(footer) > cols {
+		cols = len(footer)
+	}
+	return cols
+}
+
+// Render attribute, if it has a value
+func (table *HTMLTable) attr(name string, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %s=\"%s\"", name, html.EscapeString(value))
+}
+
+// Render class and style attributes of a cell in the row and column
+func (table *HTMLTable) cellAttrs(row int, column int) string {
+	var class, style string
+	if column < len(table.columnsClass) {
+		class = table.columnsClass[column]
+	}
+
+	align := ALIGN_LEFT
+	if level := groupLevel(row); level > -1 {
+		align = groupAlign(table.groupsAlign, level)
+	} else if column < len(table.columnsAlign) {
+		align = table.columnsAlign[column]
+	}
+	alignName := map[int]string{ALIGN_LEFT: "left", ALIGN_CENTER: "center", ALIGN_RIGHT: "right"}[align]
+	if table.alignMode == HTML_ALIGN_CLASS {
+		class = strings.TrimSpace(class + " align-" + alignName)
+	} else {
+		style = "text-align: " + alignName
+	}
+
+	return table.attr("class", class) + table.attr("style", style)
+}
+
+// Escape cell data. ANSI sequences are either converted or stripped.
+func (table *HTMLTable) escape(data string) string {
+	if table.ansiColors {
+		data = table.ansiToHTML(data)
+	} else {
+		data = html.EscapeString(table.stripAnsiRegex.ReplaceAllString(data, ""))
+	}
+	data = strings.ReplaceAll(data, "\r\n", "<br>")
+	return strings.ReplaceAll(data, "\n", "<br>")
+}
+
+// Render row of cells. Cells, covered by spans of other cells, are skipped.
+func (table *HTMLTable) renderRow(tag string, rowIdx int, cells []string, cols int, attrs string) string {
+	var row strings.Builder
+	row.WriteString("    <tr" + attrs + ">\n")
+	for idx := 0; idx < cols; idx++ {
+		if table.Data().cellOrigin(rowIdx, idx) != [2]int{rowIdx, idx} {
+			continue
+		}
+		var cell string
+		if idx < len(cells) {
+			cell = table.escape(cells[idx])
+		}
+		var spanAttrs string
+		rows, columns := table.Data().getSpan(rowIdx, idx)
+		if columns > 1 {
+			spanAttrs += table.attr("colspan", strconv.Itoa(columns))
+		}
+		if rows > 1 {
+			spanAttrs += table.attr("rowspan", strconv.Itoa(rows))
+		}
+		row.WriteString(fmt.Sprintf("      <%s%s%s>%s</%s>\n", tag, spanAttrs, table.cellAttrs(rowIdx, idx), cell, tag))
+	}
+	row.WriteString("    </tr>\n")
+	return row.String()
+}
+
+// Render table to the writer
+func (table *HTMLTable) render(writer io.Writer) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	cols := table.getColsNum()
+	if _, err := io.WriteString(writer, "<table"+table.attr("class", table.tableClass)+">\n"); err != nil {
+		return err
+	}
+
+	if len(*table.Data().GetHeader()) > 0 {
+		// Header groups are rows above the header
+		var thead strings.Builder
+		for level := range table.Data().GetHeaderGroups() {
+			thead.WriteString(table.renderRow("th", groupRow(level), table.Data().groupCells(level), cols, ""))
+		}
+		thead.WriteString(table.renderRow("th", _rowHeader, *table.Data().GetHeader(), cols, ""))
+		if _, err := io.WriteString(writer, "  <thead>\n"+thead.String()+"  </thead>\n"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(writer, "  <tbody>\n"); err != nil {
+		return err
+	}
+	for idx, row := range *table.Data().GetData() {
+		class := strings.TrimSpace(table.rowClass + " " + table.rowsClass[idx])
+		if _, err := io.WriteString(writer, table.renderRow("td", idx, row, cols, table.attr("class", class))); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(writer, "  </tbody>\n"); err != nil {
+		return err
+	}
+
+	if footer := table.Data().GetFooter(); footer != nil {
+		if _, err := io.WriteString(writer, "  <tfoot>\n"+table.renderRow("td", _rowFooter, footer, cols, "")+"  </tfoot>\n"); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(writer, "</table>\n")
+	return err
+}
+
+// Text attributes, collected from SGR sequences
+type htmlTextStyle struct {
+	color      string
+	background string
+	bold       bool
+	italic     bool
+	underline  bool
+	strike     bool
+}
+
+// CSS declarations of the style
+func (style htmlTextStyle) css() string {
+	declarations := make([]string, 0)
+	if style.color != "" {
+		declarations = append(declarations, "color: "+style.color)
+	}
+	if style.background != "" {
+		declarations = append(declarations, "background-color: "+style.background)
+	}
+	if style.bold {
+		declarations = append(declarations, "font-weight: bold")
+	}
+	if style.italic {
+		declarations = append(declarations, "font-style: italic")
+	}
+	if style.underline && style.strike {
+		declarations = append(declarations, "text-decoration: underline line-through")
+	} else if style.underline {
+		declarations = append(declarations, "text-decoration: underline")
+	} else if style.strike {
+		declarations = append(declarations, "text-decoration: line-through")
+	}
+	return strings.Join(declarations, "; ")
+}
+
+// Update style with the parameters of SGR sequence
+func (style *htmlTextStyle) apply(params []int) {
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	for idx := 0; idx < len(params); idx++ {
+		code := params[idx]
+		switch {
+		case code == 0:
+			*style = htmlTextStyle{}
+		case code == 1:
+			style.bold = true
+		case code == 3:
+			style.italic = true
+		case code == 4:
+			style.underline = true
+		case code == 9:
+			style.strike = true
+		case code == 22:
+			style.bold = false
+		case code == 23:
+			style.italic = false
+		case code == 24:
+			style.underline = false
+		case code == 29:
+			style.strike = false
+		case code >= 30 && code <= 37:
+			style.color = ansiColorHex(code - 30)
+		case code >= 90 && code <= 97:
+			style.color = ansiColorHex(code - 90 + 8)
+		case code == 39:
+			style.color = ""
+		case code >= 40 && code <= 47:
+			style.background = ansiColorHex(code - 40)
+		case code >= 100 && code <= 107:
+			style.background = ansiColorHex(code - 100 + 8)
+		case code == 49:
+			style.background = ""
+		case code == 38 || code == 48:
+			var color string
+			if idx+2 < len(params) && params[idx+1] == 5 {
+				color = ansiColorHex(params[idx+2])
+				idx += 2
+			} else if idx+4 < len(params) && params[idx+1] == 2 {
+				color = fmt.Sprintf("#%02x%02x%02x", params[idx+2]&0xff, params[idx+3]&0xff, params[idx+4]&0xff)
+				idx += 4
+			} else {
+				return
+			}
+			if code == 38 {
+				style.color = color
+			} else {
+				style.background = color
+			}
+		}
+	}
+}
+
+// Convert ANSI SGR sequences in the data into span elements with inline styles.
+// Other escape sequences are dropped.
+func (table *HTMLTable) ansiToHTML(data string) string {
+	var converted strings.Builder
+	var style htmlTextStyle
+	spanOpen := false
+	offset := 0
+
+	for _, loc := range append(table.stripAnsiRegex.FindAllStringIndex(data, -1), []int{len(data), len(data)}) {
+		if text := data[offset:loc[0]]; text != "" {
+			if !spanOpen && style.css() != "" {
+				converted.WriteString("<span style=\"" + style.css() + "\">")
+				spanOpen = true
+			}
+			converted.WriteString(html.EscapeString(text))
+		}
+		offset = loc[1]
+
+		sequence := data[loc[0]:loc[1]]
+		if !strings.HasPrefix(sequence, "\u001b[") || !strings.HasSuffix(sequence, "m") {
+			continue
+		}
+		params := make([]int, 0)
+		for _, param := range strings.Split(sequence[2:len(sequence)-1], ";") {
+			value, _ := strconv.Atoi(param) // Empty parameter means zero
+			params = append(params, value)
+		}
+		previous := style
+		style.apply(params)
+		if spanOpen && style != previous {
+			converted.WriteString("</span>")
+			spanOpen = false
+		}
+	}
+	if spanOpen {
+		converted.WriteString("</span>")
+	}
+
+	return converted.String()
+}
@@ -0,0 +1,129 @@
+package asciitable
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHTMLEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		colors   bool
+		data     string
+		expected string
+	}{
+		{"plain", false, "alpha", "alpha"},
+		{"html", false, `<b>"bold"</b> & 'x'`, "&lt;b&gt;&#34;bold&#34;&lt;/b&gt; &amp; &#39;x&#39;"},
+		{"newlines", false, "one\ntwo\r\nthree", "one<br>two<br>three"},
+		{"ansi stripped", false, "\x1b[31m<red>\x1b[0m", "&lt;red&gt;"},
+		{"ansi converted", true, "\x1b[31m<red>\x1b[0m", `<span style="color: #cd0000">&lt;red&gt;</span>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if escaped := NewHTMLTable(nil).SetAnsiColors(test.colors).escape(test.data); escaped != test.expected {
+				t.Errorf("escape(%q) = %q, expected %q", test.data, escaped, test.expected)
+			}
+		})
+	}
+}
+
+func TestAnsiToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"no escapes", "plain", "plain"},
+		{"color", "\x1b[32mok\x1b[0m done", `<span style="color: #00cd00">ok</span> done`},
+		{"bright color", "\x1b[91mhot\x1b[m", `<span style="color: #ff0000">hot</span>`},
+		{"attributes", "\x1b[1;3;4mall\x1b[0m", `<span style="font-weight: bold; font-style: italic; text-decoration: underline">all</span>`},
+		{"underline and strike", "\x1b[4;9mx\x1b[0m", `<span style="text-decoration: underline line-through">x</span>`},
+		{"palette", "\x1b[38;5;196mx\x1b[0m", `<span style="color: #ff0000">x</span>`},
+		{"gray", "\x1b[48;5;244mx\x1b[0m", `<span style="background-color: #808080">x</span>`},
+		{"rgb", "\x1b[38;2;1;2;3mx\x1b[0m", `<span style="color: #010203">x</span>`},
+		{"style changes", "\x1b[31ma\x1b[1mb\x1b[22mc", `<span style="color: #cd0000">a</span><span style="color: #cd0000; font-weight: bold">b</span><span style="color: #cd0000">c</span>`},
+		{"background reset", "\x1b[41ma\x1b[49mb", `<span style="background-color: #cd0000">a</span>b`},
+		{"not sgr", "\x1b[2Kline", "line"},
+		{"unclosed", "\x1b[34mblue", `<span style="color: #0000ee">blue</span>`},
+	}
+	table := NewHTMLTable(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if converted := table.ansiToHTML(test.data); converted != test.expected {
+				t.Errorf("ansiToHTML(%q) = %q, expected %q", test.data, converted, test.expected)
+			}
+		})
+	}
+}
+
+func TestHTMLRender(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("Host", "Size").AddRow("alpha", 10).AddRow("beta", 32)
+	}
+	tests := []struct {
+		name     string
+		table    *HTMLTable
+		expected string
+	}{
+		{"header and rows", NewHTMLTable(data()).SetColAlign(ALIGN_RIGHT, 1),
+			"<table>\n  <thead>\n    <tr>\n" +
+				"      <th style=\"text-align: left\">Host</th>\n      <th style=\"text-align: right\">Size</th>\n" +
+				"    </tr>\n  </thead>\n  <tbody>\n    <tr>\n" +
+				"      <td style=\"text-align: left\">alpha</td>\n      <td style=\"text-align: right\">10</td>\n" +
+				"    </tr>\n    <tr>\n" +
+				"      <td style=\"text-align: left\">beta</td>\n      <td style=\"text-align: right\">32</td>\n" +
+				"    </tr>\n  </tbody>\n</table>\n"},
+		{"footer", NewHTMLTable(data().SetFooter("Total").SetFooterAggregate(AGGREGATE_SUM, 1)).SetColAlign(ALIGN_CENTER, -1),
+			"<table>\n  <thead>\n    <tr>\n" +
+				"      <th style=\"text-align: center\">Host</th>\n      <th style=\"text-align: center\">Size</th>\n" +
+				"    </tr>\n  </thead>\n  <tbody>\n    <tr>\n" +
+				"      <td style=\"text-align: center\">alpha</td>\n      <td style=\"text-align: center\">10</td>\n" +
+				"    </tr>\n    <tr>\n" +
+				"      <td style=\"text-align: center\">beta</td>\n      <td style=\"text-align: center\">32</td>\n" +
+				"    </tr>\n  </tbody>\n  <tfoot>\n    <tr>\n" +
+				"      <td style=\"text-align: center\">Total</td>\n      <td style=\"text-align: center\">42</td>\n" +
+				"    </tr>\n  </tfoot>\n</table>\n"},
+		{"classes", NewHTMLTable(data()).SetAlignMode(HTML_ALIGN_CLASS).SetTableClass("hosts").
+			SetColClass("name", 0).SetRowClass("row", -1).SetRowClass("odd", 1),
+			"<table class=\"hosts\">\n  <thead>\n    <tr>\n" +
+				"      <th class=\"name align-left\">Host</th>\n      <th class=\"align-left\">Size</th>\n" +
+				"    </tr>\n  </thead>\n  <tbody>\n    <tr class=\"row\">\n" +
+				"      <td class=\"name align-left\">alpha</td>\n      <td class=\"align-left\">10</td>\n" +
+				"    </tr>\n    <tr class=\"row odd\">\n" +
+				"      <td class=\"name align-left\">beta</td>\n      <td class=\"align-left\">32</td>\n" +
+				"    </tr>\n  </tbody>\n</table>\n"},
+		{"no header", NewHTMLTable(NewTableData().AddRow("<x>")),
+			"<table>\n  <tbody>\n    <tr>\n      <td style=\"text-align: left\">&lt;x&gt;</td>\n    </tr>\n  </tbody>\n</table>\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rendered := test.table.Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestHTMLErrors(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("Host").AddRow("alpha")
+	}
+	tests := []struct {
+		name  string
+		table *HTMLTable
+		err   error
+	}{
+		{"align", NewHTMLTable(data()).SetColAlign(42, 0), ErrInvalidOption},
+		{"align column", NewHTMLTable(data()).SetColAlign(ALIGN_LEFT, 1), ErrColumnOutOfRange},
+		{"align mode", NewHTMLTable(data()).SetAlignMode(42), ErrInvalidOption},
+		{"row class", NewHTMLTable(data()).SetRowClass("x", 1), ErrRowOutOfRange},
+		{"column class", NewHTMLTable(data()).SetColClass("x", -2), ErrColumnOutOfRange},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.table.RenderTo(&failingWriter{}); !errors.Is(err, test.err) {
+				t.Errorf("RenderTo() = %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package asciitable
+
+// Line weights of the junction engine
+const (
+	_lineNone = iota
+	_lineThin
+	_lineThick
+	_lineDouble
+	_lineDashed
+	_lineAscii
+	_lineAsciiDouble
+)
+
+// Box-drawing glyphs by weights of the lines, meeting at the point: up, right, down, left.
+// Unicode has all thin and thick combinations, but double lines join only thin ones.
+var _junctions = map[[4]int]string{
+	{0, 0, 0, 1}: "\u2574", // ╴
+	{0, 0, 0, 2}: "\u2578", // ╸
+	{0, 0, 1, 0}: "\u2577", // ╷
+	{0, 0, 1, 1}: "\u2510", // ┐
+	{0, 0, 1, 2}: "\u2511", // ┑
+	{0, 0, 1, 3}: "\u2555", // ╕
+	{0, 0, 2, 0}: "\u257b", // ╻
+	{0, 0, 2, 1}: "\u2512", // ┒
+	{0, 0, 2, 2}: "\u2513", // ┓
+	{0, 0, 3, 1}: "\u2556", // ╖
+	{0, 0, 3, 3}: "\u2557", // ╗
+	{0, 1, 0, 0}: "\u2576", // ╶
+	{0, 1, 0, 1}: "\u2500", // ─
+	{0, 1, 0, 2}: "\u257e", // ╾
+	{0, 1, 1, 0}: "\u250c", // ┌
+	{0, 1, 1, 1}: "\u252c", // ┬
+	{0, 1, 1, 2}: "\u252d", // ┭
+	{0, 1, 2, 0}: "\u250e", // ┎
+	{0, 1, 2, 1}: "\u2530", // ┰
+	{0, 1, 2, 2}: "\u2531", // ┱
+	{0, 1, 3, 0}: "\u2553", // ╓
+	{0, 1, 3, 1}: "\u2565", // ╥
+	{0, 2, 0, 0}: "\u257a", // ╺
+	{0, 2, 0, 1}: "\u257c", // ╼
+	{0, 2, 0, 2}: "\u2501", // ━
+	{0, 2, 1, 0}: "\u250d", // ┍
+	{0, 2, 1, 1}: "\u252e", // ┮
+	{0, 2, 1, 2}: "\u252f", // ┯
+	{0, 2, 2, 0}: "\u250f", // ┏
+	{0, 2, 2, 1}: "\u2532", // ┲
+	{0, 2, 2, 2}: "\u2533", // ┳
+	{0, 3, 0, 3}: "\u2550", // ═
+	{0, 3, 1, 0}: "\u2552", // ╒
+	{0, 3, 1, 3}: "\u2564", // ╤
+	{0, 3, 3, 0}: "\u2554", // ╔
+	{0, 3, 3, 3}: "\u2566", // ╦
+	{1, 0, 0, 0}: "\u2575", // ╵
+	{1, 0, 0, 1}: "\u2518", // ┘
+	{1, 0, 0, 2}: "\u2519", // ┙
+	{1, 0, 0, 3}: "\u255b", // ╛
+	{1, 0, 1, 0}: "\u2502", // │
+	{1, 0, 1, 1}: "\u2524", // ┤
+	{1, 0, 1, 2}: "\u2525", // ┥
+	{1, 0, 1, 3}: "\u2561", // ╡
+	{1, 0, 2, 0}: "\u257d", // ╽
+	{1, 0, 2, 1}: "\u2527", // ┧
+	{1, 0, 2, 2}: "\u252a", // ┪
+	{1, 1, 0, 0}: "\u2514", // └
+	{1, 1, 0, 1}: "\u2534", // ┴
+	{1, 1, 0, 2}: "\u2535", // ┵
+	{1, 1, 1, 0}: "\u251c", // ├
+	{1, 1, 1, 1}: "\u253c", // ┼
+	{1, 1, 1, 2}: "\u253d", // ┽
+	{1, 1, 2, 0}: "\u251f", // ┟
+	{1, 1, 2, 1}: "\u2541", // ╁
+	{1, 1, 2, 2}: "\u2545", // ╅
+	{1, 2, 0, 0}: "\u2515", // ┕
+	{1, 2, 0, 1}: "\u2536", // ┶
+	{1, 2, 0, 2}: "\u2537", // ┷
+	{1, 2, 1, 0}: "\u251d", // ┝
+	{1, 2, 1, 1}: "\u253e", // ┾
+	{1, 2, 1, 2}: "\u253f", // ┿
+	{1, 2, 2, 0}: "\u2522", // ┢
+	{1, 2, 2, 1}: "\u2546", // ╆
+	{1, 2, 2, 2}: "\u2548", // ╈
+	{1, 3, 0, 0}: "\u2558", // ╘
+	{1, 3, 0, 3}: "\u2567", // ╧
+	{1, 3, 1, 0}: "\u255e", // ╞
+	{1, 3, 1, 3}: "\u256a", // ╪
+	{2, 0, 0, 0}: "\u2579", // ╹
+	{2, 0, 0, 1}: "\u251a", // ┚
+	{2, 0, 0, 2}: "\u251b", // ┛
+	{2, 0, 1, 0}: "\u257f", // ╿
+	{2, 0, 1, 1}: "\u2526", // ┦
+	{2, 0, 1, 2}: "\u2529", // ┩
+	{2, 0, 2, 0}: "\u2503", // ┃
+	{2, 0, 2, 1}: "\u2528", // ┨
+	{2, 0, 2, 2}: "\u252b", // ┫
+	{2, 1, 0, 0}: "\u2516", // ┖
+	{2, 1, 0, 1}: "\u2538", // ┸
+	{2, 1, 0, 2}: "\u2539", // ┹
+	{2, 1, 1, 0}: "\u251e", // ┞
+	{2, 1, 1, 1}: "\u2540", // ╀
+	{2, 1, 1, 2}: "\u2543", // ╃
+	{2, 1, 2, 0}: "\u2520", // ┠
+	{2, 1, 2, 1}: "\u2542", // ╂
+	{2, 1, 2, 2}: "\u2549", // ╉
+	{2, 2, 0, 0}: "\u2517", // ┗
+	{2, 2, 0, 1}: "\u253a", // ┺
+	{2, 2, 0, 2}: "\u253b", // ┻
+	{2, 2, 1, 0}: "\u2521", // ┡
+	{2, 2, 1, 1}: "\u2544", // ╄
+	{2, 2, 1, 2}: "\u2547", // ╇
+	{2, 2, 2, 0}: "\u2523", // ┣
+	{2, 2, 2, 1}: "\u254a", // ╊
+	{2, 2, 2, 2}: "\u254b", // ╋
+	{3, 0, 0, 1}: "\u255c", // ╜
+	{3, 0, 0, 3}: "\u255d", // ╝
+	{3, 0, 3, 0}: "\u2551", // ║
+	{3, 0, 3, 1}: "\u2562", // ╢
+	{3, 0, 3, 3}: "\u2563", // ╣
+	{3, 1, 0, 0}: "\u2559", // ╙
+	{3, 1, 0, 1}: "\u2568", // ╨
+	{3, 1, 3, 0}: "\u255f", // ╟
+	{3, 1, 3, 1}: "\u256b", // ╫
+	{3, 3, 0, 0}: "\u255a", // ╚
+	{3, 3, 0, 3}: "\u2569", // ╩
+	{3, 3, 3, 0}: "\u2560", // ╠
+	{3, 3, 3, 3}: "\u256c", // ╬
+}
+
+// Get line weight of the border style. Unknown styles are drawn in ASCII.
+func lineWeight(style int) int {
+	switch style {
+	case BORDER_NONE:
+		return _lineNone
+	case BORDER_SINGLE_THIN:
+		return _lineThin
+	case BORDER_SINGLE_THICK:
+		return _lineThick
+	case BORDER_DOUBLE:
+		return _lineDouble
+	case BORDER_DASHED:
+		return _lineDashed
+	}
+	return _lineAscii
+}
+
+/*
+Get glyph, where lines of the given weights meet: up, right, down and left.
+Combinations, missing in Unicode, degrade to the closest one: dashed lines
+join as thin, double and thick lines join as the one with more arms,
+and double lines, which still can not be joined, become thin.
+*/
+func junction(up int, right int, down int, left int) string {
+	arms := [4]int{up, right, down, left}
+	weights := make(map[int]int)
+	for _, weight := range arms {
+		weights[weight]++
+	}
+	horizontal := up == _lineNone && down == _lineNone
+	vertical := left == _lineNone && right == _lineNone
+
+	switch {
+	case weights[_lineNone] == len(arms):
+		return ""
+	case weights[_lineAscii] > 0 || weights[_lineAsciiDouble] > 0:
+		if horizontal && weights[_lineAsciiDouble] > 0 {
+			return "="
+		} else if horizontal {
+			return "-"
+		} else if vertical {
+			return "|"
+		}
+		return "+"
+	case weights[_lineDashed] > 0 && weights[_lineDashed]+weights[_lineNone] == len(arms) && (horizontal || vertical):
+		if horizontal {
+			return "\u2504"
+		}
+		return "\u2506"
+	}
+
+	replace := func(from int, to int) {
+		for idx := range arms {
+			if arms[idx] == from {
+				arms[idx] = to
+			}
+		}
+	}
+
+	replace(_lineDashed, _lineThin)
+	if weights[_lineDouble] > 0 && weights[_lineThick] > 0 {
+		if weights[_lineDouble] > weights[_lineThick] {
+			replace(_lineThick, _lineDouble)
+		} else {
+			replace(_lineDouble, _lineThick)
+		}
+	}
+	if glyph, ok := _junctions[arms]; ok {
+		return glyph
+	}
+
+	replace(_lineDouble, _lineThin)
+	return _junctions[arms]
+}
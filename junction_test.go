@@ -0,0 +1,53 @@
+package asciitable
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJunction(t *testing.T) {
+	tests := []struct {
+		name                  string
+		up, right, down, left int
+		expected              string
+	}{
+		{"none", _lineNone, _lineNone, _lineNone, _lineNone, ""},
+		{"thin cross", _lineThin, _lineThin, _lineThin, _lineThin, "┼"},
+		{"thin top", _lineNone, _lineThin, _lineThin, _lineThin, "┬"},
+		{"thick corner", _lineNone, _lineThick, _lineThick, _lineNone, "┏"},
+		{"thick header on thin", _lineThin, _lineThick, _lineThin, _lineThick, "┿"},
+		{"double header on thin", _lineThin, _lineDouble, _lineThin, _lineDouble, "╪"},
+		{"double outer, thin inner", _lineNone, _lineDouble, _lineThin, _lineDouble, "╤"},
+		{"thin on double outer", _lineDouble, _lineThin, _lineDouble, _lineNone, "╟"},
+		{"dashed line", _lineNone, _lineDashed, _lineNone, _lineDashed, "┄"},
+		{"dashed vertical", _lineDashed, _lineNone, _lineDashed, _lineNone, "┆"},
+		{"dashed joins as thin", _lineThin, _lineDashed, _lineThin, _lineDashed, "┼"},
+		{"double and thick join as more arms", _lineDouble, _lineThick, _lineDouble, _lineNone, "╠"},
+		{"double and thick tie as thick", _lineThick, _lineDouble, _lineNone, _lineThin, "┺"},
+		{"double, which can not join, becomes thin", _lineDouble, _lineDouble, _lineThin, _lineThin, "┼"},
+		{"ascii cross", _lineAscii, _lineThin, _lineAscii, _lineThin, "+"},
+		{"ascii line", _lineNone, _lineAscii, _lineNone, _lineAscii, "-"},
+		{"ascii double line", _lineNone, _lineAsciiDouble, _lineNone, _lineAsciiDouble, "="},
+		{"ascii vertical", _lineAscii, _lineNone, _lineAscii, _lineNone, "|"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if glyph := junction(test.up, test.right, test.down, test.left); glyph != test.expected {
+				t.Errorf("junction(%d, %d, %d, %d) = %q, expected %q", test.up, test.right, test.down, test.left, glyph, test.expected)
+			}
+		})
+	}
+}
+
+func TestLineStyleErrors(t *testing.T) {
+	for name, style := range map[string]*BorderStyle{
+		"header":    NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetHeaderStyle(42),
+		"footer":    NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetFooterStyle(42),
+		"rows":      NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetRowSeparator(42),
+		"separator": NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetColSeparator(42, 0),
+	} {
+		if err := style.Validate(); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%s: Validate() = %v, expected %v", name, err, ErrInvalidOption)
+		}
+	}
+}
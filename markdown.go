@@ -0,0 +1,249 @@
+package asciitable
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// MarkdownTable renders table data as a GitHub-flavored pipe table.
+type MarkdownTable struct {
+	rowsData       *TableData
+	columnsAlign   []int
+	stripAnsiRegex *regexp.Regexp
+	measure        *displayWidth
+	err            error
+}
+
+/*
+NewMarkdownTable object constructor
+*/
+func NewMarkdownTable(data *TableData) *MarkdownTable {
+	table := new(MarkdownTable)
+	if data == nil {
+		data = NewTableData()
+	}
+	table.rowsData = data
+
+	table.columnsAlign = make([]int, data.GetColsNum())
+	for idx := range table.columnsAlign {
+		table.columnsAlign[idx] = data.getColAlignHint(idx)
+	}
+
+	table.stripAnsiRegex = regexp.MustCompile(_ansiRegex)
+	table.measure = newDisplayWidth()
+
+	return table
+}
+
+// Markdown returns Markdown table over the same data with the same columns align.
+func (table *SimpleTable) Markdown() *MarkdownTable {
+	markdown := NewMarkdownTable(table.Data())
+	markdown.columnsAlign = make([]int, len(table.columnsAlign))
+	copy(markdown.columnsAlign, table.columnsAlign)
+
+	return markdown
+}
+
+// Set column align
+func (table *MarkdownTable) SetColAlign(align int, columns ...int) *MarkdownTable {
+	if align != ALIGN_LEFT && align != ALIGN_RIGHT && align != ALIGN_CENTER {
+		table.setError(fmt.Errorf("SetColAlign: unknown align %d: %w", align, ErrInvalidOption))
+		return table
+	}
+
+	// Data might be added after the table was created
+	for len(table.columnsAlign) < table.getColsNum() {
+		table.columnsAlign = append(table.columnsAlign, table.Data().getColAlignHint(len(table.columnsAlign)))
+	}
+
+	// Set align to all cells
+	if len(columns) == 1 && columns[0] == -1 {
+		for idx := range table.columnsAlign {
+			table.columnsAlign[idx] = align
+		}
+	} else {
+		// Set only specific cells
+		for _, column := range columns {
+			if column >= 0 && column < len(table.columnsAlign) {
+				table.columnsAlign[column] = align
+			} else {
+				table.setError(fmt.Errorf("SetColAlign: column %d: %w", column, ErrColumnOutOfRange))
+			}
+		}
+	}
+
+	return table
+}
+
+// Record the error, unless there is one already
+func (table *MarkdownTable) setError(err error) {
+	if table.err == nil {
+		table.err = err
+	}
+}
+
+// Validate returns the first error, occurred while the table was configured,
+// or an error of the table data.
+func (table *MarkdownTable) Validate() error {
+	if table.err != nil {
+		return table.err
+	}
+	return table.Data().Err()
+}
+
+// Returns table data
+func (table *MarkdownTable) Data() *TableData {
+	return table.rowsData
+}
+
+// Renders table as a string. Empty string is returned, if the table is not valid.
+func (table *MarkdownTable) Render() string {
+	var rendered strings.Builder
+	table.render(&rendered) // strings.Builder never returns write errors
+	return rendered.String()
+}
+
+// RenderTo writes the table to the writer, row by row.
+func (table *MarkdownTable) RenderTo(writer io.Writer) error {
+	buff := bufio.NewWriter(writer)
+	if err := table.render(buff); err != nil {
+		return err
+	}
+	return buff.Flush()
+}
+
+// HTML special characters, which Markdown passes through as HTML otherwise
+var _markdownHTMLReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escape cell data: strip ANSI sequences, escape HTML and pipes and turn newlines
+// into breaks, since all the cell must stay on one line.
+func (table *MarkdownTable) escape(data string) string {
+	data = table.stripAnsiRegex.ReplaceAllString(data, "")
+	data = _markdownHTMLReplacer.Replace(data)
+	data = strings.ReplaceAll(data, "|", "\\|")
+	data = strings.ReplaceAll(data, "\r\n", "<br>")
+	return strings.ReplaceAll(data, "\n", "<br>")
+}
+
+// Get number of columns. Header and rows might be of a different length.
+func (table *MarkdownTable) getColsNum() int {
+	cols := len(*table.Data().GetHeader())
+	for _, row := range *table.Data().GetData() {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	if footer := table.Data().GetFooter(); len(footer) > cols {
+		cols = len(footer)
+	}
+	return cols
+}
+
+// Get column align. Columns without explicit align are left-aligned.
+func (table *MarkdownTable) getColAlign(column int) int {
+	if column < len(table.columnsAlign) {
+		return table.columnsAlign[column]
+	}
+	return ALIGN_LEFT
+}
+
+// Calculate column widths, so the source of the table is also readable.
+// Delimiter row needs at least three dashes.
+func (table *MarkdownTable) getColWidths() []int {
+	widths := make([]int, table.getColsNum())
+	for idx := range widths {
+		widths[idx] = 3
+	}
+
+	measureRow := func(row []string) {
+		for idx, cell := range row {
+			if width := table.measure.width(table.escape(cell)); width > widths[idx] {
+				widths[idx] = width
+			}
+		}
+	}
+	measureRow(table.Data().flatHeader())
+	for _, row := range *table.Data().GetData() {
+		measureRow(row)
+	}
+	measureRow(table.Data().GetFooter())
+
+	return widths
+}
+
+// Render row of cells, padded to the column widths
+func (table *MarkdownTable) renderRow(cells []string, widths []int) string {
+	var row strings.Builder
+	row.WriteString("|")
+	for idx, width := range widths {
+		var cell string
+		if idx < len(cells) {
+			cell = table.escape(cells[idx])
+		}
+		pad := width - table.measure.width(cell)
+		switch table.getColAlign(idx) {
+		case ALIGN_RIGHT:
+			cell = strings.Repeat(" ", pad) + cell
+		case ALIGN_CENTER:
+			cell = strings.Repeat(" ", pad/2) + cell + strings.Repeat(" ", pad-pad/2)
+		default:
+			cell = cell + strings.Repeat(" ", pad)
+		}
+		row.WriteString(" " + cell + " |")
+	}
+	return row.String()
+}
+
+// Render delimiter row, which is also defining columns align
+func (table *MarkdownTable) renderDelimiter(widths []int) string {
+	var row strings.Builder
+	row.WriteString("|")
+	for idx, width := range widths {
+		switch table.getColAlign(idx) {
+		case ALIGN_RIGHT:
+			row.WriteString(" " + strings.Repeat("-", width-1) + ": |")
+		case ALIGN_CENTER:
+			row.WriteString(" :" + strings.Repeat("-", width-2) + ": |")
+		default:
+			row.WriteString(" :" + strings.Repeat("-", width-1) + " |")
+		}
+	}
+	return row.String()
+}
+
+// Render table to the writer. Header row is mandatory in Markdown,
+// so it is rendered empty, if the table has no header. Markdown has
+// a single header row, so header groups are joined with column titles.
+func (table *MarkdownTable) render(writer io.Writer) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	widths := table.getColWidths()
+	if len(widths) == 0 {
+		return nil
+	}
+
+	lines := []string{table.renderRow(table.Data().flatHeader(), widths), table.renderDelimiter(widths)}
+	for _, line := range lines {
+		if _, err := io.WriteString(writer, line+"\n"); err != nil {
+			return err
+		}
+	}
+
+	// Markdown has no footer, so it goes as the last row
+	rows := *table.Data().GetData()
+	if footer := table.Data().GetFooter(); footer != nil {
+		rows = append(append(make([][]string, 0, len(rows)+1), rows...), footer)
+	}
+	for _, row := range rows {
+		if _, err := io.WriteString(writer, table.renderRow(row, widths)+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package asciitable
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarkdownAlign(t *testing.T) {
+	tests := []struct {
+		name     string
+		align    int
+		expected string
+	}{
+		{"left", ALIGN_LEFT, "| Name  |\n| :---- |\n| alpha |\n"},
+		{"center", ALIGN_CENTER, "| Name  |\n| :---: |\n| alpha |\n"},
+		{"right", ALIGN_RIGHT, "|  Name |\n| ----: |\n| alpha |\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewMarkdownTable(NewTableData().SetHeader("Name").AddRow("alpha")).SetColAlign(test.align, 0)
+			if rendered := table.Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"plain", "alpha", "alpha"},
+		{"pipe", "a|b", "a\\|b"},
+		{"newline", "one\ntwo", "one<br>two"},
+		{"crlf", "one\r\ntwo", "one<br>two"},
+		{"ansi", "\x1b[31mred\x1b[0m", "red"},
+		{"html", "<b>bold</b> & <br>", "&lt;b&gt;bold&lt;/b&gt; &amp; &lt;br&gt;"},
+		{"entity", "&lt;", "&amp;lt;"},
+	}
+	table := NewMarkdownTable(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if escaped := table.escape(test.data); escaped != test.expected {
+				t.Errorf("escape(%q) = %q, expected %q", test.data, escaped, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     *TableData
+		expected string
+	}{
+		{"rows", NewTableData().SetHeader("Host", "Size").AddRow("alpha", 10).AddRow("beta", 2),
+			"| Host  | Size |\n| :---- | :--- |\n| alpha | 10   |\n| beta  | 2    |\n"},
+		{"footer as the last row", NewTableData().SetHeader("Host", "Size").AddRow("alpha", 10).AddRow("beta", 2).
+			SetFooter("Total").SetFooterAggregate(AGGREGATE_SUM, 1),
+			"| Host  | Size |\n| :---- | :--- |\n| alpha | 10   |\n| beta  | 2    |\n| Total | 12   |\n"},
+		{"empty header", NewTableData().AddRow("alpha", "a|b"),
+			"|       |      |\n| :---- | :--- |\n| alpha | a\\|b |\n"},
+		{"wide characters", NewTableData().SetHeader("Name").AddRow("日本語"),
+			"| Name   |\n| :----- |\n| 日本語 |\n"},
+		{"no data", NewTableData(), ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rendered := NewMarkdownTable(test.data).Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownErrors(t *testing.T) {
+	table := NewMarkdownTable(NewTableData().SetHeader("Name")).SetColAlign(ALIGN_LEFT, 1)
+	if err := table.Validate(); !errors.Is(err, ErrColumnOutOfRange) {
+		t.Errorf("Validate() = %v, expected %v", err, ErrColumnOutOfRange)
+	}
+	if err := table.RenderTo(&failingWriter{}); !errors.Is(err, ErrColumnOutOfRange) {
+		t.Errorf("RenderTo() = %v, expected %v", err, ErrColumnOutOfRange)
+	}
+	if err := NewMarkdownTable(NewTableData().SetHeader("Name")).SetColAlign(42, 0).Validate(); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Validate() = %v, expected %v", err, ErrInvalidOption)
+	}
+}
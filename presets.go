@@ -0,0 +1,96 @@
+package asciitable
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Constructors of named styles. Each call returns a new style, so it can be changed freely.
+var (
+	_stylesLock sync.RWMutex
+	_styles     = map[string]func() *BorderStyle{
+		"ascii": func() *BorderStyle {
+			return NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetHeaderStyle(BORDER_SINGLE_THIN)
+		},
+		"rounded": func() *BorderStyle {
+			return NewCustomBorderStyle(
+				BorderOuter{VERTICAL_LINE: "│", HORISONTAL_LINE: "─",
+					LEFT_TOP: "╭", RIGHT_TOP: "╮", LEFT_BOTTOM: "╰", RIGHT_BOTTOM: "╯"},
+				BorderInner{VERTICAL_LINE: "│", HORISONTAL_LINE: "─",
+					LEFT_MIDDLE: "├", CENTER_TOP: "┬", CENTER_BOTTOM: "┴", CENTER_MIDDLE: "┼", RIGHT_MIDDLE: "┤",
+					HEADER: "─", HEADER_LEFT: "├", HEADER_MIDDLE: "┼", HEADER_RIGHT: "┤"})
+		},
+		"heavy": func() *BorderStyle {
+			return NewBorderStyle(BORDER_SINGLE_THICK, BORDER_SINGLE_THICK).SetHeaderStyle(BORDER_SINGLE_THICK)
+		},
+		"double": func() *BorderStyle {
+			return NewBorderStyle(BORDER_DOUBLE, BORDER_DOUBLE).SetHeaderStyle(BORDER_DOUBLE)
+		},
+		"compact": func() *BorderStyle {
+			return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetBorderVisible(false).
+				SetColSeparator(BORDER_NONE, -1).SetRowSeparator(BORDER_NONE).SetHeaderStyle(BORDER_SINGLE_THIN)
+		},
+		"psql": func() *BorderStyle {
+			return NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetBorderVisible(false).
+				SetRowSeparator(BORDER_NONE).SetHeaderStyle(BORDER_SINGLE_THIN)
+		},
+		"mysql": func() *BorderStyle {
+			return NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetRowSeparator(BORDER_NONE).SetHeaderStyle(BORDER_SINGLE_THIN)
+		},
+		"markdown-like": func() *BorderStyle {
+			return NewCustomBorderStyle(
+				BorderOuter{VERTICAL_LINE: "|"},
+				BorderInner{VERTICAL_LINE: "|", HEADER: "-", HEADER_LEFT: "|", HEADER_MIDDLE: "|", HEADER_RIGHT: "|"})
+		},
+		"borderless": func() *BorderStyle {
+			return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetBorderVisible(false).
+				SetColSeparator(BORDER_NONE, -1).SetRowSeparator(BORDER_NONE)
+		},
+		"restructuredtext": func() *BorderStyle {
+			return NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetHeaderStyle(BORDER_DOUBLE)
+		},
+	}
+)
+
+/*
+RegisterStyle adds named style to the registry, or replaces existing one,
+including built-in presets. Constructor must return a new style on each call.
+*/
+func RegisterStyle(name string, constructor func() *BorderStyle) error {
+	if name == "" || constructor == nil {
+		return fmt.Errorf("RegisterStyle: name and constructor are required: %w", ErrInvalidOption)
+	}
+
+	_stylesLock.Lock()
+	defer _stylesLock.Unlock()
+	_styles[name] = constructor
+
+	return nil
+}
+
+// GetStyle returns a new style, registered with the name, or ErrUnknownStyle.
+func GetStyle(name string) (*BorderStyle, error) {
+	_stylesLock.RLock()
+	constructor, ok := _styles[name]
+	_stylesLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("style %q: %w", name, ErrUnknownStyle)
+	}
+	return constructor(), nil
+}
+
+// StyleNames returns names of all registered styles in alphabetical order.
+func StyleNames() []string {
+	_stylesLock.RLock()
+	defer _stylesLock.RUnlock()
+
+	names := make([]string, 0, len(_styles))
+	for name := range _styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
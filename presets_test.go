@@ -0,0 +1,86 @@
+package asciitable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStylePresets(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"ascii", "\n+-----+----+\n|Host |Size|\n+-----+----+\n|alpha|10  |\n+-----+----+\n|beta |2   |\n+-----+----+"},
+		{"borderless", "\nHost  Size\nalpha 10  \nbeta  2   "},
+		{"compact", "\nHost  Size\n──────────\nalpha 10  \nbeta  2   "},
+		{"double", "\n╔═════╦════╗\n║Host ║Size║\n╠═════╬════╣\n║alpha║10  ║\n╠═════╬════╣\n║beta ║2   ║\n╚═════╩════╝"},
+		{"heavy", "\n┏━━━━━┳━━━━┓\n┃Host ┃Size┃\n┣━━━━━╋━━━━┫\n┃alpha┃10  ┃\n┣━━━━━╋━━━━┫\n┃beta ┃2   ┃\n┗━━━━━┻━━━━┛"},
+		{"markdown-like", "\n|Host |Size|\n|-----|----|\n|alpha|10  |\n|beta |2   |"},
+		{"mysql", "\n+-----+----+\n|Host |Size|\n+-----+----+\n|alpha|10  |\n|beta |2   |\n+-----+----+"},
+		{"psql", "\nHost |Size\n-----+----\nalpha|10  \nbeta |2   "},
+		{"restructuredtext", "\n+-----+----+\n|Host |Size|\n+=====+====+\n|alpha|10  |\n+-----+----+\n|beta |2   |\n+-----+----+"},
+		{"rounded", "\n╭─────┬────╮\n│Host │Size│\n├─────┼────┤\n│alpha│10  │\n├─────┼────┤\n│beta │2   │\n╰─────┴────╯"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			style, err := GetStyle(test.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := style.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			data := NewTableData().SetHeader("Host", "Size").AddRow("alpha", 10).AddRow("beta", 2)
+			if rendered := NewSimpleTable(data, style.SetGlyphMode(GLYPHS_UNICODE)).Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestStyleNames(t *testing.T) {
+	expected := []string{"ascii", "borderless", "compact", "double", "heavy", "markdown-like",
+		"mysql", "psql", "restructuredtext", "rounded"}
+	if names := StyleNames(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("StyleNames() = %q, expected %q", names, expected)
+	}
+}
+
+func TestGetStyle(t *testing.T) {
+	if _, err := GetStyle("fancy"); !errors.Is(err, ErrUnknownStyle) {
+		t.Errorf("GetStyle() = %v, expected %v", err, ErrUnknownStyle)
+	}
+
+	// Each call returns a new style
+	style, _ := GetStyle("ascii")
+	style.SetBorderVisible(false)
+	if style, _ := GetStyle("ascii"); !style.outer.IS_VISIBLE {
+		t.Error("GetStyle() returned a style, changed by the previous caller")
+	}
+}
+
+func TestRegisterStyle(t *testing.T) {
+	ascii := _styles["ascii"]
+	defer func() {
+		_styles["ascii"] = ascii
+		delete(_styles, "plain")
+	}()
+
+	plain := func() *BorderStyle { return NewBorderStyle(BORDER_NONE, BORDER_NONE) }
+	for _, name := range []string{"plain", "ascii"} {
+		if err := RegisterStyle(name, plain); err != nil {
+			t.Fatal(err)
+		}
+		if style, err := GetStyle(name); err != nil || style.outer.style != BORDER_NONE {
+			t.Errorf("GetStyle(%q) = %v, %v, expected registered style", name, style, err)
+		}
+	}
+
+	if err := RegisterStyle("", plain); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("RegisterStyle() = %v, expected %v", err, ErrInvalidOption)
+	}
+	if err := RegisterStyle("empty", nil); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("RegisterStyle() = %v, expected %v", err, ErrInvalidOption)
+	}
+}
@@ -0,0 +1,154 @@
+package asciitable
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ResizeWatcher re-renders a table, when terminal is resized.
+type ResizeWatcher struct {
+	table    *SimpleTable
+	output   io.Writer
+	callback func(cols int, rows int)
+	lines    []int // Widths of the lines, written by the last render
+	lock     sync.Mutex
+}
+
+/*
+NewResizeWatcher object constructor. Watcher updates width of the table
+to the terminal width on each resize (SIGWINCH), re-renders the table in place
+to the output, if any, and notifies the callback, if any.
+*/
+func NewResizeWatcher(table *SimpleTable) *ResizeWatcher {
+	watcher := new(ResizeWatcher)
+	watcher.table = table
+	return watcher
+}
+
+// Set output, where the table is rendered and re-rendered in place on resize
+func (watcher *ResizeWatcher) SetOutput(output io.Writer) *ResizeWatcher {
+	watcher.output = output
+	return watcher
+}
+
+// Set callback, called with the new terminal size after the table is updated
+func (watcher *ResizeWatcher) SetCallback(callback func(cols int, rows int)) *ResizeWatcher {
+	watcher.callback = callback
+	return watcher
+}
+
+/*
+Update applies changes to the table and re-renders it in place. While the watcher
+runs, the table should be changed only this way, since resize updates it concurrently.
+*/
+func (watcher *ResizeWatcher) Update(update func(table *SimpleTable)) error {
+	watcher.lock.Lock()
+	defer watcher.lock.Unlock()
+
+	if update != nil {
+		update(watcher.table)
+	}
+	return watcher.redraw(0)
+}
+
+/*
+Run renders the table to the output and watches terminal resizes, until
+the context is done. Error is returned, if resize can not be watched
+on this platform or rendering fails.
+*/
+func (watcher *ResizeWatcher) Run(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	stop, err := notifyResize(signals)
+	if err != nil {
+		return err
+	}
+	defer stop()
+
+	if err := watcher.Update(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			if err := watcher.resize(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Update table width to the terminal and re-render it. COLUMNS and LINES
+// keep the size at startup, so the terminal is queried directly.
+func (watcher *ResizeWatcher) resize() error {
+	cols, rows, err := terminalSize(false)
+	if err != nil {
+		return nil // Keep the current layout, until the size is known again
+	}
+
+	watcher.lock.Lock()
+	watcher.table.SetWidth(cols)
+	err = watcher.redraw(cols)
+	watcher.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if watcher.callback != nil {
+		watcher.callback(cols, rows)
+	}
+	return nil
+}
+
+/*
+Erase previous render and write the table again. Terminals re-flow long lines on
+resize, so the previous render takes more lines, if the terminal got narrower.
+Zero cols means the terminal has not been resized.
+*/
+func (watcher *ResizeWatcher) redraw(cols int) error {
+	if watcher.output == nil {
+		return nil
+	}
+
+	var rendered strings.Builder
+	if err := watcher.table.renderTo(&rendered, watcher.output); err != nil {
+		return err
+	}
+
+	// Previous render is erased from column 0 of its first line, keeping the line
+	// above it, where the render has started, intact.
+	output := rendered.String()
+	if len(watcher.lines) > 0 {
+		up := -1
+		for _, width := range watcher.lines {
+			up++
+			if cols > 0 && width > cols {
+				up += (width - 1) / cols
+			}
+		}
+
+		erase := "\r\x1b[J"
+		if up > 0 {
+			erase = fmt.Sprintf("\x1b[%dF\x1b[J", up)
+		}
+		output = erase + strings.TrimPrefix(output, "\n")
+	}
+	if _, err := io.WriteString(watcher.output, output); err != nil {
+		return err
+	}
+
+	// Render starts with a line break, so the first line is the current one
+	lines := strings.Split(rendered.String(), "\n")
+	watcher.lines = make([]int, 0, len(lines))
+	for _, line := range lines[1:] {
+		watcher.lines = append(watcher.lines, watcher.table.textWidth(line))
+	}
+
+	return nil
+}
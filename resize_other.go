@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package asciitable
+
+import (
+	"errors"
+	"os"
+)
+
+// Terminal resize signals are not available on this platform
+func notifyResize(signals chan<- os.Signal) (func(), error) {
+	return nil, errors.New("terminal resize notifications are not supported on this platform")
+}
@@ -0,0 +1,50 @@
+package asciitable
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestResizeRedraw(t *testing.T) {
+	table := "\n+-----+\n|Host |\n|alpha|\n+-----+"
+	tests := []struct {
+		name     string
+		cols     int
+		expected string
+	}{
+		{"same width", 0, table + "\x1b[3F\x1b[J" + table[1:]},
+		{"wide enough", 7, table + "\x1b[3F\x1b[J" + table[1:]},
+		// Each line of 7 cells takes two lines of 4 cells
+		{"re-flowed", 4, table + "\x1b[7F\x1b[J" + table[1:]},
+		{"re-flowed to single cells", 1, table + "\x1b[27F\x1b[J" + table[1:]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			watcher := NewResizeWatcher(NewSimpleTable(NewTableData().SetHeader("Host").AddRow("alpha"), nil)).SetOutput(&output)
+			if err := watcher.Update(nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := watcher.redraw(test.cols); err != nil {
+				t.Fatal(err)
+			}
+			if output.String() != test.expected {
+				t.Errorf("redraw(%d) = %q, expected %q", test.cols, output.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestResizeRedrawSingleLine(t *testing.T) {
+	var output bytes.Buffer
+	table := NewSimpleTable(NewTableData().AddRow("alpha"), NewBorderStyle(BORDER_ASCII, BORDER_ASCII).SetBorderVisible(false))
+	watcher := NewResizeWatcher(table).SetOutput(&output)
+	for i := 0; i < 2; i++ {
+		if err := watcher.Update(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected := "\nalpha\r\x1b[Jalpha"; output.String() != expected {
+		t.Errorf("Update() = %q, expected %q", output.String(), expected)
+	}
+}
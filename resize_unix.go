@@ -0,0 +1,16 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package asciitable
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Subscribe to terminal resize signals. Returned function stops the subscription.
+func notifyResize(signals chan<- os.Signal) (func(), error) {
+	signal.Notify(signals, syscall.SIGWINCH)
+	return func() { signal.Stop(signals) }, nil
+}
@@ -1,8 +1,12 @@
 package asciitable
 
 import (
-	"github.com/isbm/textwrap"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -12,30 +16,62 @@ const (
 	_borderInner
 	_borderBottom
 	_borderHeader
+	_borderFooter
+	_borderGroup
 	_ansiRegex = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 )
 
-// Table object
-type simpleTable struct {
-	rowsData        *TableData
-	rowsCount       uint64
-	headerAlign     int
-	columnsAlign    []int
-	columnsTextWrap []bool
-	style           *borderStyle
-	widthTable      int
-	widthColumns    []int
-	widthData       int
-	padding         int
-	wrapText        bool
-	stripAnsiRegex  *regexp.Regexp
+// Column widths, computed once and reused by all rendered rows and borders.
+// Layout is valid as long as table settings, data and style stay the same.
+type tableLayout struct {
+	widths        []int
+	dataRevision  uint64
+	styleRevision uint64
+	valid         bool
+}
+
+// Position of the cut, when data does not fit into the cell.
+const (
+	TRUNCATE_END = iota
+	TRUNCATE_START
+	TRUNCATE_MIDDLE
+)
+
+// SimpleTable renders table data as a text table with borders.
+type SimpleTable struct {
+	rowsData         *TableData
+	rowsCount        uint64
+	headerAlign      int
+	columnsAlign     []int
+	columnsTextWrap  []bool
+	style            *BorderStyle
+	widthTable       int
+	widthColumns     []int
+	widthData        int
+	layout           tableLayout
+	padding          int
+	wrapText         bool
+	stripAnsiRegex   *regexp.Regexp
+	measure          *displayWidth
+	ellipsis         string
+	truncatePosition int
+	title            string
+	titlePosition    int
+	caption          string
+	groupsAlign      map[int]int       // Align of the header group levels, -1 is for all
+	colTextStyles    map[int]string    // SGR sequences of columns
+	rowTextStyles    map[int]string    // SGR sequences of data rows
+	cellTextStyles   map[[2]int]string // SGR sequences of cells by row and column
+	colorMode        int
+	colors           bool // Colors are enabled for the current render
+	err              error
 }
 
 /*
 NewSimpleTable object constructor
 */
-func NewSimpleTable(data *TableData, style *borderStyle) *simpleTable {
-	table := new(simpleTable)
+func NewSimpleTable(data *TableData, style *BorderStyle) *SimpleTable {
+	table := new(SimpleTable)
 	if data == nil {
 		data = NewTableData()
 	}
@@ -47,7 +83,7 @@ func NewSimpleTable(data *TableData, style *borderStyle) *simpleTable {
 	table.columnsAlign = make([]int, data.GetColsNum())
 	table.columnsTextWrap = make([]bool, len(table.columnsAlign))
 	for idx := range table.columnsAlign {
-		table.columnsAlign[idx] = ALIGN_LEFT
+		table.columnsAlign[idx] = data.getColAlignHint(idx)
 		table.columnsTextWrap[idx] = true
 	}
 
@@ -57,47 +93,131 @@ func NewSimpleTable(data *TableData, style *borderStyle) *simpleTable {
 	}
 	table.style = style
 
-	table.widthTable, _ = GetTerminalSize()
-	table.widthColumns = make([]int, 0)
+	table.widthTable, _, _ = GetTerminalSize() // Default size is returned on errors
+	table.widthColumns = data.getColWidthHints()
 	table.padding = 0
 	table.wrapText = false
 	table.stripAnsiRegex = regexp.MustCompile(_ansiRegex)
+	table.measure = newDisplayWidth()
+	table.ellipsis = "..."
+	table.truncatePosition = TRUNCATE_END
+	table.titlePosition = TITLE_ABOVE
+	table.groupsAlign = make(map[int]int)
+	table.colTextStyles = make(map[int]string)
+	table.rowTextStyles = make(map[int]string)
+	table.cellTextStyles = make(map[[2]int]string)
+	table.colorMode = COLOR_AUTO
+
+	return table
+}
 
+/*
+Set color mode: COLOR_AUTO (default) writes colors only to terminals, honoring
+NO_COLOR, FORCE_COLOR and TERM=dumb, COLOR_ALWAYS and COLOR_NEVER force either way.
+Without colors all escape sequences are stripped from the cells as well.
+*/
+func (table *SimpleTable) SetColorMode(mode int) *SimpleTable {
+	if mode != COLOR_AUTO && mode != COLOR_ALWAYS && mode != COLOR_NEVER {
+		table.setError(fmt.Errorf("SetColorMode: unknown mode %d: %w", mode, ErrInvalidOption))
+		return table
+	}
+	table.colorMode = mode
 	return table
 }
 
+// Check if colors are enabled for the output
+func (table *SimpleTable) colorEnabled(output io.Writer) bool {
+	switch table.colorMode {
+	case COLOR_ALWAYS:
+		return true
+	case COLOR_NEVER:
+		return false
+	}
+	return colorSupported(isTerminal(output))
+}
+
+// Apply SGR sequence to the text, if colors are enabled
+func (table *SimpleTable) paint(sgr string, text string) string {
+	if !table.colors {
+		return text
+	}
+	return paintText(sgr, text)
+}
+
 // SetWrapText wraps text in all cells instead of trimming it to the max width.
-func (table *simpleTable) SetTextWrap(wrap bool) *simpleTable {
+func (table *SimpleTable) SetTextWrap(wrap bool) *SimpleTable {
 	table.wrapText = wrap
 	return table
 }
 
 // Set cell padding
-func (table *simpleTable) SetCellPadding(width int) *simpleTable {
+func (table *SimpleTable) SetCellPadding(width int) *SimpleTable {
 	table.padding = width
+	table.invalidateLayout()
+	return table
+}
+
+/*
+Set ellipsis, which marks truncated data, e.g. "\u2026" or "...".
+Empty string truncates data without any mark.
+*/
+func (table *SimpleTable) SetEllipsis(ellipsis string) *SimpleTable {
+	table.ellipsis = ellipsis
+	return table
+}
+
+/*
+Set where data, not fitting into the cell, is cut: TRUNCATE_END (default),
+TRUNCATE_START or TRUNCATE_MIDDLE, which is useful for long file paths.
+*/
+func (table *SimpleTable) SetTruncatePosition(position int) *SimpleTable {
+	if position != TRUNCATE_END && position != TRUNCATE_START && position != TRUNCATE_MIDDLE {
+		table.setError(fmt.Errorf("SetTruncatePosition: unknown position %d: %w", position, ErrInvalidOption))
+		return table
+	}
+	table.truncatePosition = position
+	return table
+}
+
+/*
+Set width of East Asian Ambiguous characters, such as Greek, Cyrillic or some
+symbols: AMBIGUOUS_NARROW (default) or AMBIGUOUS_WIDE for CJK terminals.
+*/
+func (table *SimpleTable) SetAmbiguousWidth(width int) *SimpleTable {
+	if width != AMBIGUOUS_NARROW && width != AMBIGUOUS_WIDE {
+		table.setError(fmt.Errorf("SetAmbiguousWidth: unknown width %d: %w", width, ErrInvalidOption))
+		return table
+	}
+	table.measure.ambiguous = width
+	table.invalidateLayout()
 	return table
 }
 
 /*
 Set overall table width (chars)
 */
-func (table *simpleTable) SetWidth(width int) *simpleTable {
+func (table *SimpleTable) SetWidth(width int) *SimpleTable {
 	table.widthTable = width
+	table.invalidateLayout()
 	return table
 }
 
+// SetTableWidth sets overall table width, as SetWidth does, for the Sizer interface
+func (table *SimpleTable) SetTableWidth(width int) Sizer {
+	return table.SetWidth(width)
+}
+
 /*
 Set column width (chars). If columns contains only one value and it is -1,
 then width applies to all columns at once.
 */
-func (table *simpleTable) SetColWidth(width int, columns ...int) *simpleTable {
-	colsNum := table.Data().GetColsNum()
+func (table *SimpleTable) SetColWidth(width int, columns ...int) *SimpleTable {
+	colsNum := table.fitColumns()
 	if colsNum == 0 {
-		panic("An attempt to set columns while no header or data has been set")
-	} else if len(table.widthColumns) == 0 {
-		table.widthColumns = make([]int, colsNum)
-	} else if len(columns) > colsNum {
-		panic("An attempt to set more columns widths than actually in the table")
+		table.setError(fmt.Errorf("SetColWidth: %w", ErrNoData))
+		return table
+	} else if len(table.widthColumns) < colsNum {
+		table.widthColumns = append(table.widthColumns, make([]int, colsNum-len(table.widthColumns))...)
 	}
 
 	// Set width to all cells
@@ -108,20 +228,21 @@ func (table *simpleTable) SetColWidth(width int, columns ...int) *simpleTable {
 	} else {
 		// Set only specific cells
 		for _, column := range columns {
-			if column < colsNum {
+			if column >= 0 && column < colsNum {
 				table.widthColumns[column] = width
 			} else {
-				panic("Attempt to set width of a column that does not exist")
+				table.setError(fmt.Errorf("SetColWidth: column %d: %w", column, ErrColumnOutOfRange))
 			}
 		}
 	}
+	table.invalidateLayout()
 
 	return table
 }
 
 // Set column text wrap
-func (table *simpleTable) SetColTextWrap(wrap bool, columns ...int) *simpleTable {
-	colsNum := table.Data().GetColsNum()
+func (table *SimpleTable) SetColTextWrap(wrap bool, columns ...int) *SimpleTable {
+	colsNum := table.fitColumns()
 
 	// Set wrapping to all columns
 	if len(columns) == 1 && columns[0] == -1 {
@@ -131,10 +252,10 @@ func (table *simpleTable) SetColTextWrap(wrap bool, columns ...int) *simpleTable
 	} else {
 		// Set only specific columns
 		for _, column := range columns {
-			if column < colsNum {
+			if column >= 0 && column < colsNum {
 				table.columnsTextWrap[column] = wrap
 			} else {
-				panic("Attempt to set text wrapping attribute to a column that does not exist")
+				table.setError(fmt.Errorf("SetColTextWrap: column %d: %w", column, ErrColumnOutOfRange))
 			}
 		}
 	}
@@ -143,19 +264,19 @@ func (table *simpleTable) SetColTextWrap(wrap bool, columns ...int) *simpleTable
 }
 
 // Set column align
-func (table *simpleTable) SetColAlign(align int, columns ...int) *simpleTable {
+func (table *SimpleTable) SetColAlign(align int, columns ...int) *SimpleTable {
 	if align != ALIGN_LEFT && align != ALIGN_RIGHT && align != ALIGN_CENTER {
-		panic("An attempt to set an unknown align")
+		table.setError(fmt.Errorf("SetColAlign: unknown align %d: %w", align, ErrInvalidOption))
+		return table
 	}
 
-	colsNum := table.Data().GetColsNum()
-
+	colsNum := table.fitColumns()
 	if colsNum == 0 {
-		panic("An attempt to set column align while no header or data has been set")
+		table.setError(fmt.Errorf("SetColAlign: %w", ErrNoData))
+		return table
 	} else if len(table.widthColumns) == 0 {
 		table.widthColumns = make([]int, colsNum)
-	} else if len(columns) > colsNum {
-		panic("An attempt to set more column align than actually in the table")
+		table.invalidateLayout()
 	}
 
 	// Set align to all cells
@@ -166,10 +287,10 @@ func (table *simpleTable) SetColAlign(align int, columns ...int) *simpleTable {
 	} else {
 		// Set only specific cells
 		for _, column := range columns {
-			if column < colsNum {
+			if column >= 0 && column < colsNum {
 				table.columnsAlign[column] = align
 			} else {
-				panic("Attempt to set width of a column that does not exist")
+				table.setError(fmt.Errorf("SetColAlign: column %d: %w", column, ErrColumnOutOfRange))
 			}
 		}
 	}
@@ -177,26 +298,174 @@ func (table *simpleTable) SetColAlign(align int, columns ...int) *simpleTable {
 	return table
 }
 
+/*
+Set colors and attributes of the columns. If columns contains only one value
+and it is -1, then style applies to all columns at once. Empty style removes it.
+*/
+func (table *SimpleTable) SetColTextStyle(textStyle TextStyle, columns ...int) *SimpleTable {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		table.setError(fmt.Errorf("SetColTextStyle: %w", err))
+		return table
+	}
+
+	colsNum := table.fitColumns()
+	if len(columns) == 1 && columns[0] == -1 {
+		columns = make([]int, colsNum)
+		for idx := range columns {
+			columns[idx] = idx
+		}
+	}
+	for _, column := range columns {
+		if column < 0 || column >= colsNum {
+			table.setError(fmt.Errorf("SetColTextStyle: column %d: %w", column, ErrColumnOutOfRange))
+		} else if sgr == "" {
+			delete(table.colTextStyles, column)
+		} else {
+			table.colTextStyles[column] = sgr
+		}
+	}
+
+	return table
+}
+
+// Set colors and attributes of the data rows. Empty style removes it.
+func (table *SimpleTable) SetRowTextStyle(textStyle TextStyle, rows ...int) *SimpleTable {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		table.setError(fmt.Errorf("SetRowTextStyle: %w", err))
+		return table
+	}
+
+	for _, row := range rows {
+		if row < 0 || row >= table.Data().GetRowsNum() {
+			table.setError(fmt.Errorf("SetRowTextStyle: row %d: %w", row, ErrRowOutOfRange))
+		} else if sgr == "" {
+			delete(table.rowTextStyles, row)
+		} else {
+			table.rowTextStyles[row] = sgr
+		}
+	}
+
+	return table
+}
+
+// Set colors and attributes of the data cell. Empty style removes it.
+func (table *SimpleTable) SetCellTextStyle(textStyle TextStyle, row int, column int) *SimpleTable {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		table.setError(fmt.Errorf("SetCellTextStyle: %w", err))
+		return table
+	}
+
+	if row < 0 || row >= table.Data().GetRowsNum() {
+		table.setError(fmt.Errorf("SetCellTextStyle: row %d: %w", row, ErrRowOutOfRange))
+	} else if column < 0 || column >= table.fitColumns() {
+		table.setError(fmt.Errorf("SetCellTextStyle: column %d: %w", column, ErrColumnOutOfRange))
+	} else if sgr == "" {
+		delete(table.cellTextStyles, [2]int{row, column})
+	} else {
+		table.cellTextStyles[[2]int{row, column}] = sgr
+	}
+
+	return table
+}
+
+// Get number of columns. Header and rows might be of a different length.
+func (table *SimpleTable) getColsNum() int {
+	cols := len(*table.Data().GetHeader())
+	if cols == 0 {
+		cols = table.Data().GetColsNum()
+	}
+	return cols
+}
+
+// Extend column settings, since data might be added after the table was created.
+// Returns number of columns.
+func (table *SimpleTable) fitColumns() int {
+	colsNum := table.getColsNum()
+	for len(table.columnsAlign) < colsNum {
+		table.columnsAlign = append(table.columnsAlign, table.Data().getColAlignHint(len(table.columnsAlign)))
+	}
+	for len(table.columnsTextWrap) < colsNum {
+		table.columnsTextWrap = append(table.columnsTextWrap, true)
+	}
+	return colsNum
+}
+
+// Record the error, unless there is one already
+func (table *SimpleTable) setError(err error) {
+	if table.err == nil {
+		table.err = err
+	}
+}
+
+/*
+Validate returns the first error, occurred while the table was configured,
+or an error of the table data. Render returns an empty string and RenderTo
+returns the error, if the table is not valid.
+*/
+func (table *SimpleTable) Validate() error {
+	if table.err != nil {
+		return table.err
+	}
+	if err := table.Data().Err(); err != nil {
+		return err
+	}
+	if err := table.style.Validate(); err != nil {
+		return err
+	}
+
+	colsNum := table.getColsNum()
+	if colsNum == 0 {
+		return ErrNoData
+	}
+	for idx, row := range *table.Data().GetData() {
+		if len(row) > colsNum {
+			return fmt.Errorf("row %d has %d columns, but table has %d: %w", idx, len(row), colsNum, ErrColumnOutOfRange)
+		}
+	}
+	if footer := table.Data().GetFooter(); len(footer) > colsNum {
+		return fmt.Errorf("footer has %d columns, but table has %d: %w", len(footer), colsNum, ErrColumnOutOfRange)
+	}
+	if err := table.Data().validateSpans(colsNum); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Returns table data
-func (table *simpleTable) Data() *TableData {
+func (table *SimpleTable) Data() *TableData {
 	return table.rowsData
 }
 
 // Allow support ANSI-colored data. If the data is not stripped out,
 // all the widths will be wrongly calculated
-func (table *simpleTable) stripAnsi(data string) string {
+func (table *SimpleTable) stripAnsi(data string) string {
 	return table.stripAnsiRegex.ReplaceAllString(data, "")
 }
 
+// Get visible width of the data in terminal cells. ANSI sequences are not counted,
+// wide characters take two cells, combining characters take none.
+func (table *SimpleTable) textWidth(data string) int {
+	return table.measure.width(table.stripAnsi(data))
+}
+
 // Sets maximum data width. Used to decide either table is narrower
 // then the terminal or not. Normally should be called after
 // data bulk update, since it is quite expensive.
-func (table *simpleTable) setDataMaxWidth() int {
+func (table *SimpleTable) setDataMaxWidth() int {
+	rows := *table.Data().GetData()
+	if footer := table.Data().GetFooter(); footer != nil {
+		rows = append(append(make([][]string, 0, len(rows)+1), rows...), footer)
+	}
+
 	width := 0
-	for _, row := range *table.Data().GetData() {
+	for _, row := range rows {
 		rowWidth := 0
 		for _, cell := range row {
-			rowWidth += len(table.stripAnsi(cell))
+			rowWidth += table.textWidth(cell)
 		}
 		if rowWidth > width {
 			width = rowWidth
@@ -207,26 +476,65 @@ func (table *simpleTable) setDataMaxWidth() int {
 	return table.widthData
 }
 
-// Calculate row widths for maximum widest data
-// XXX: Should be able to cache the results and do not re-calculate it from scratch for each row and element!!
-func (table *simpleTable) getRowWidths() []int {
-	widths := make([]int, len(*table.Data().GetHeader()))
+// Drop cached layout, so it is calculated again on next render.
+func (table *SimpleTable) invalidateLayout() {
+	table.layout.valid = false
+}
 
-	for idx, title := range *table.Data().GetHeader() {
-		titleLength := len(table.stripAnsi(title)) + table.padding*2
-		if titleLength >= widths[idx] {
-			widths[idx] = titleLength
-		}
+// Get row widths for maximum widest data. Widths are calculated only once
+// and then cached until table settings, data or style are changed.
+func (table *SimpleTable) getRowWidths() []int {
+	if !table.layout.valid || table.layout.dataRevision != table.Data().revision ||
+		table.layout.styleRevision != table.style.revision {
+		table.setDataMaxWidth()
+		table.layout.widths = table.calcRowWidths()
+		table.layout.dataRevision = table.Data().revision
+		table.layout.styleRevision = table.style.revision
+		table.layout.valid = true
 	}
 
-	for _, rowData := range *table.Data().GetData() {
-		for idx, data := range rowData {
-			dataLength := len(table.stripAnsi(data)) + table.padding*2
-			if dataLength > widths[idx] {
-				widths[idx] = dataLength
+	return table.layout.widths
+}
+
+// Calculate row widths for maximum widest data. This scans all the data,
+// so use getRowWidths instead, which is caching the result.
+func (table *SimpleTable) calcRowWidths() []int {
+	widths := make([]int, table.getColsNum())
+
+	// Cells, spanning several columns, are measured after the columns
+	spanning := make([][2]int, 0)
+	measureRow := func(row int, cells []string) {
+		for idx, data := range cells {
+			if _, columns := table.Data().getSpan(row, idx); columns > 1 {
+				spanning = append(spanning, [2]int{row, idx})
+			} else if idx < len(widths) && table.Data().cellOrigin(row, idx) == [2]int{row, idx} {
+				if dataLength := table.textWidth(data) + table.padding*2; dataLength > widths[idx] {
+					widths[idx] = dataLength
+				}
 			}
 		}
 	}
+	for level := range table.Data().GetHeaderGroups() {
+		measureRow(groupRow(level), table.Data().groupCells(level))
+	}
+	measureRow(_rowHeader, *table.Data().GetHeader())
+	for idx, rowData := range *table.Data().GetData() {
+		measureRow(idx, rowData)
+	}
+	measureRow(_rowFooter, table.Data().GetFooter())
+
+	// The last spanned column takes the rest of the cell
+	for _, cell := range spanning {
+		_, columns := table.Data().getSpan(cell[0], cell[1])
+		last := cell[1] + columns - 1
+		if last >= len(widths) {
+			continue
+		}
+		dataLength := table.textWidth(table.rowCells(cell[0])[cell[1]]) + table.padding*2
+		if width := table.spanWidth(widths, cell[1], last); dataLength > width {
+			widths[last] += dataLength - width
+		}
+	}
 
 	// Override custom widths
 	if len(table.widthColumns) == len(widths) {
@@ -263,121 +571,203 @@ func (table *simpleTable) getRowWidths() []int {
 				widths[len(widths)-1] = lastColWidth
 			}
 		}
-	} else {
-		defaultWidth := table.widthTable/table.Data().GetColsNum() - 1
+	} else if colsNum := table.getColsNum(); colsNum > 0 {
+		// Table, narrower than its columns, has no room for data at all
+		defaultWidth := table.widthTable/colsNum - 1
+		if defaultWidth < 0 {
+			defaultWidth = 0
+		}
 		for idx := range widths {
 			widths[idx] = defaultWidth
 		}
 		// set last column width
-		lastColumnWidth := (table.widthTable - (defaultWidth * (table.Data().GetColsNum() - 1))) - table.Data().GetColsNum() + 1
+		lastColumnWidth := (table.widthTable - (defaultWidth * (colsNum - 1))) - colsNum + 1
 		if table.style.outer.IS_VISIBLE {
 			lastColumnWidth -= 2
 		}
+		if lastColumnWidth < 0 {
+			lastColumnWidth = 0
+		}
 		widths[len(widths)-1] = lastColumnWidth
 	}
 
 	return widths
 }
 
-// Support ANSI escape
-func (table *simpleTable) align(data string, width int, direction int) string {
-	strippedDataLen := len(table.stripAnsi(data))
-	pad := width - strippedDataLen
+// Support ANSI escape. Reset is added only after data with escape sequences,
+// so they do not leak into the next cell.
+func (table *SimpleTable) align(data string, width int, direction int) string {
+	escaped := len(table.stripAnsi(data)) != len(data)
+	pad := width - table.textWidth(data)
+	if pad < 0 {
+		pad = 0
+	}
 	switch direction {
 	case ALIGN_RIGHT:
 		data = strings.Repeat(" ", pad) + data
 	case ALIGN_CENTER:
-		data = strings.Repeat(" ", pad/2) + data + strings.Repeat(" ", pad/2)
+		data = strings.Repeat(" ", pad/2) + data + strings.Repeat(" ", pad-pad/2)
 	default:
 		data = data + strings.Repeat(" ", pad)
 	}
 
-	if len(data) != strippedDataLen {
+	if table.colors && escaped {
 		data += "\u001b[0m"
 	}
 
 	return data
 }
 
-func (table *simpleTable) renderCell(data string, width int, first bool, align int) string {
-	// Trim data, if width is smaller
-	if len(table.stripAnsi(data)) > width {
-		data = data[:width-3-(table.padding*2)] + "..."
+func (table *SimpleTable) renderCell(data string, width int, first bool, align int, sgr string) string {
+	if !table.colors {
+		data = table.stripAnsi(data)
 	}
+	// Trim data, if width is smaller
+	data = table.truncateAnsi(data, width-table.padding*2)
 
-	return table.align(strings.Repeat(" ", table.padding)+data+strings.Repeat(" ", table.padding), width, align)
+	return table.paint(sgr, table.align(strings.Repeat(" ", table.padding)+data+strings.Repeat(" ", table.padding), width, align))
 }
 
 /*
 Renders border, but not exactly a *border* but row between the table data,
 which is typically either top of the header (outer border of the table),
 row under the header, row between the regular cells or bottom row (outer
-border of the table, again).
+border of the table, again). Upper and lower rows are those above and below
+the border: junctions lose their arms, where cells of the rows span columns.
+Cells, spanning both rows, continue over the border with the content line,
+given by the content function, or nil, if there are none.
 */
-func (table *simpleTable) renderBorder(borderType int) string {
-	rowWidths := table.getRowWidths()
-	var border string
+func (table *SimpleTable) renderBorder(borderType int, upper int, lower int, content func(origin [2]int) string) string {
+	style := table.style
+	var left, line, right string
+	var middle func(column int) string
 	switch borderType {
 	case _borderTop:
-		border = table.style.outer.LeftTop()
-		for idx, width := range rowWidths {
-			border += strings.Repeat(table.style.outer.HorisontalLine(), width)
-			if idx < len(rowWidths)-1 {
-				border += table.style.inner.CenterTop()
-			} else {
-				border += table.style.outer.RightTop()
-			}
-		}
+		left, line, right = style.outer.LeftTop(), style.outer.HorisontalLine(), style.outer.RightTop()
+		middle = func(column int) string { return style.separator(column).CenterTop() }
 	case _borderBottom:
-		border = table.style.outer.LeftBottom()
-		for idx, width := range rowWidths {
-			border += strings.Repeat(table.style.outer.HorisontalLine(), width)
-			if idx < len(rowWidths)-1 {
-				border += table.style.inner.CenterBottom()
-			} else {
-				border += table.style.outer.RightBottom()
+		left, line, right = style.outer.LeftBottom(), style.outer.HorisontalLine(), style.outer.RightBottom()
+		middle = func(column int) string { return style.separator(column).CenterBottom() }
+	case _borderInner:
+		left, line, right = style.inner.LeftMiddle(), style.inner.HorisontalLine(), style.inner.RightMiddle()
+		middle = func(column int) string { return style.separator(column).CenterMiddle() }
+	case _borderHeader:
+		if !style.inner.HEADER_IS_VISIBLE && !style.inner.IS_VISIBLE {
+			return ""
+		}
+		line = style.inner.Header()
+		if style.outer.IS_VISIBLE {
+			left, right = style.inner.HeaderLeft(), style.inner.HeaderRight()
+		}
+		middle = func(column int) string {
+			if style.inner.IS_VISIBLE {
+				return style.separator(column).HeaderMiddle()
 			}
+			return ""
 		}
-	case _borderInner:
-		border = table.style.inner.LeftMiddle()
-		for idx, width := range rowWidths {
-			border += strings.Repeat(table.style.inner.HorisontalLine(), width)
-			if idx < len(rowWidths)-1 {
-				border += table.style.inner.CenterMiddle()
-			} else {
-				border += table.style.inner.RightMiddle()
+	case _borderGroup:
+		// Without own line header groups are apart as any other row
+		level := groupLevel(upper)
+		if style.groupSeparator(level, -1) == nil {
+			return table.renderBorder(_borderInner, upper, lower, content)
+		}
+		line = style.groupSeparator(level, -1).Header()
+		if style.outer.IS_VISIBLE {
+			left, right = style.groupSeparator(level, -1).HeaderLeft(), style.groupSeparator(level, -1).HeaderRight()
+		}
+		middle = func(column int) string {
+			if style.inner.IS_VISIBLE {
+				return style.groupSeparator(level, column).HeaderMiddle()
 			}
+			return ""
 		}
-	case _borderHeader:
-		if table.style.inner.HEADER_IS_VISIBLE || table.style.inner.IS_VISIBLE {
-			if table.style.outer.IS_VISIBLE {
-				border = table.style.inner.HeaderLeft()
+	case _borderFooter:
+		// Without own line the footer is apart as any other row
+		if style.inner.Footer() == "" {
+			return table.renderBorder(_borderInner, upper, lower, content)
+		}
+		line = style.inner.Footer()
+		if style.outer.IS_VISIBLE {
+			left, right = style.inner.FooterLeft(), style.inner.FooterRight()
+		}
+		middle = func(column int) string {
+			if style.inner.IS_VISIBLE {
+				return style.separator(column).FooterMiddle()
 			}
+			return ""
+		}
+	}
 
-			for idx, width := range rowWidths {
-				border += strings.Repeat(table.style.inner.Header(), width)
-				if idx < len(rowWidths)-1 {
-					if table.style.inner.IS_VISIBLE {
-						border += table.style.inner.HeaderMiddle()
-					}
-				} else {
-					if table.style.outer.IS_VISIBLE {
-						border += table.style.inner.HeaderRight()
-					}
-				}
+	// Arms, which junctions of the border never have, are dropped as well
+	var absent [4]bool
+	switch borderType {
+	case _borderTop:
+		absent[0] = true
+	case _borderBottom:
+		absent[2] = true
+	}
+
+	// Cell, which spans both rows, if any
+	crossing := func(column int) ([2]int, bool) {
+		if content == nil || upper < 0 || lower < 0 {
+			return [2]int{}, false
+		}
+		origin := table.Data().cellOrigin(upper, column)
+		return origin, origin == table.Data().cellOrigin(lower, column)
+	}
+	// Check if the row has a cell, which spans the column and the next one
+	merged := func(row int, column int) bool {
+		return row != _rowNone && table.Data().cellOrigin(row, column) == table.Data().cellOrigin(row, column+1)
+	}
+
+	rowWidths := table.getRowWidths()
+	var border strings.Builder
+	glyphs := left
+	if _, ok := crossing(0); ok {
+		glyphs = mergeJunction(left, [4]bool{false, true, false, true}, line, style.outer.VerticalLine())
+	}
+	for column := 0; column < len(rowWidths); {
+		last := column
+		if origin, ok := crossing(column); ok {
+			_, columns := table.Data().getSpan(origin[0], origin[1])
+			last = column + columns - 1
+			border.WriteString(table.paint(style.borderSGR, glyphs))
+			border.WriteString(table.renderCell(content(origin), table.spanWidth(rowWidths, column, last),
+				column == 0, table.cellAlign(origin[0], origin[1]), table.cellTextStyle(origin[0], origin[1])))
+			glyphs = ""
+		} else {
+			glyphs += strings.Repeat(line, rowWidths[column])
+		}
+
+		_, crossed := crossing(last)
+		if last < len(rowWidths)-1 {
+			_, next := crossing(last + 1)
+			drop := [4]bool{merged(upper, last), next, merged(lower, last), crossed}
+			if drop != [4]bool{} {
+				drop[0], drop[2] = drop[0] || absent[0], drop[2] || absent[2]
 			}
+			glyphs += mergeJunction(middle(last), drop, line, style.separator(last).VerticalLine())
+		} else if crossed {
+			glyphs += mergeJunction(right, [4]bool{false, true, false, true}, line, style.outer.VerticalLine())
+		} else {
+			glyphs += right
 		}
+		column = last + 1
+	}
+
+	if width := table.titleBorderWidth(); borderType == _borderTop && width > 0 {
+		glyphs = table.embedTitle(glyphs, width)
 	}
-	return border
+	border.WriteString(table.paint(style.borderSGR, glyphs))
+
+	return border.String()
 }
 
 // Support ANSI text attributes when wrapping data.
-func (table *simpleTable) wrapCellData(data string, width int) []string {
-	plainData := table.stripAnsi(data)
-	plainDataLen := len(plainData)
+func (table *SimpleTable) wrapCellData(data string, width int) []string {
 	var content []string
-	if plainDataLen > width {
-		content = textwrap.NewTextWrap().SetWidth(width).Wrap(plainData)
+	if table.textWidth(data) > width {
+		content = table.wrapAnsi(data, width)
 	} else {
 		content = []string{data}
 	}
@@ -385,113 +775,365 @@ func (table *simpleTable) wrapCellData(data string, width int) []string {
 	return content
 }
 
-// Pivot data vertically to columns.
-func (table *simpleTable) pivotData(data []string) [][]string {
-	rowWidths := table.getRowWidths()
-	cellBuff := make([][]string, len(data))
-	maxrows := 0
+// Get cells of the row. Header row is -1, footer row is -2.
+func (table *SimpleTable) rowCells(row int) []string {
+	switch row {
+	case _rowHeader:
+		return *table.Data().GetHeader()
+	case _rowFooter:
+		return table.Data().GetFooter()
+	}
+	if level := groupLevel(row); level > -1 {
+		return table.Data().groupCells(level)
+	}
+	return (*table.Data().GetData())[row]
+}
 
-	for cidx, cell := range data {
-		if table.columnsTextWrap[cidx] {
-			cellBuff[cidx] = table.wrapCellData(cell, rowWidths[cidx]-(table.padding*2))
-		} else {
-			cellBuff[cidx] = []string{cell}
-		}
-		if len(cellBuff[cidx]) > maxrows {
-			maxrows = len(cellBuff[cidx])
+// Get content lines of the cell. Wrapped lines are trimmed.
+func (table *SimpleTable) cellLines(row int, column int, width int) []string {
+	var data string
+	if cells := table.rowCells(row); column < len(cells) {
+		data = cells[column]
+	}
+	if !table.wrapText {
+		return []string{data}
+	}
+
+	content := []string{data}
+	if table.columnsTextWrap[column] {
+		content = table.wrapCellData(data, width-(table.padding*2))
+	}
+	for idx, line := range content {
+		content[idx] = strings.TrimSpace(line)
+	}
+	return content
+}
+
+// Get width of the columns, merged into one cell, including separators between them
+func (table *SimpleTable) spanWidth(rowWidths []int, first int, last int) int {
+	width := 0
+	for column := first; column <= last; column++ {
+		width += rowWidths[column]
+		if column < last {
+			width += table.measure.width(table.style.separator(column).VerticalLine())
 		}
 	}
+	return width
+}
 
-	pivoted := make([][]string, maxrows)
-	for colIdx := 0; colIdx < maxrows; colIdx++ {
-		pivotedRow := make([]string, len(rowWidths))
-		for dataIdx, cellData := range cellBuff {
-			if colIdx < len(cellData) {
-				pivotedRow[dataIdx] = strings.TrimSpace(cellData[colIdx])
-			} else {
-				pivotedRow[dataIdx] = ""
+// Cell of the rendered row. Cell, spanning several columns, is a single segment.
+type rowSegment struct {
+	origin [2]int
+	first  int
+	last   int
+}
+
+// Split the row into cells, which might span several columns or rows
+func (table *SimpleTable) rowSegments(row int) []rowSegment {
+	colsNum := len(table.getRowWidths())
+	segments := make([]rowSegment, 0, colsNum)
+	for column := 0; column < colsNum; {
+		origin := table.Data().cellOrigin(row, column)
+		_, columns := table.Data().getSpan(origin[0], origin[1])
+		segments = append(segments, rowSegment{origin: origin, first: column, last: column + columns - 1})
+		column += columns
+	}
+	return segments
+}
+
+/*
+Render rows from first to last and lines between them to the writer, line by
+line. Cells might span several rows within, but not across these rows. Content
+of such cells is distributed over all their lines, including lines between
+the rows, and the last row grows, if the content does not fit.
+*/
+func (table *SimpleTable) renderRows(writer io.Writer, first int, last int) error {
+	rowWidths := table.getRowWidths()
+	rowLines := table.style.inner.HorisontalLine() != ""
+
+	// Content of cells, spanning rows, is kept for all their lines. Content of
+	// other cells is only measured, unless it is a single row, and taken again,
+	// when the row is rendered, so long blocks of rows are not kept in memory.
+	keep := first == last
+	heights := make([]int, last-first+1)
+	lines := make(map[[2]int][]string)
+	spanning := make([][2]int, 0)
+	for idx := range heights {
+		row := first + idx
+		heights[idx] = 1
+		for _, segment := range table.rowSegments(row) {
+			if segment.origin[0] != row {
+				continue // Cell starts in the row above
+			}
+			content := table.cellLines(row, segment.first, table.spanWidth(rowWidths, segment.first, segment.last))
+			if rows, _ := table.Data().getSpan(row, segment.first); rows > 1 {
+				lines[segment.origin] = content
+				spanning = append(spanning, segment.origin)
+				continue
+			} else if len(content) > heights[idx] {
+				heights[idx] = len(content)
+			}
+			if keep {
+				lines[segment.origin] = content
 			}
 		}
-		pivoted[colIdx] = pivotedRow
 	}
-	return pivoted
-}
 
-// Takes padded cells data and renders to the wrapped row
-func (table *simpleTable) renderRowWrapped(cells []string) string {
-	var rendered strings.Builder
-	pivoted := table.pivotData(cells)
-	for idx, innerRow := range pivoted {
-		rendered.WriteString(table.renderRowSingle(innerRow))
-		dlen := len(pivoted)
-		if dlen > 1 && idx < dlen-1 {
-			rendered.WriteString("\n")
+	// Lines of the block, where the rows start. Starts are computed up to the row,
+	// which heights above are final, as cells, spanning rows, grow their last row.
+	starts := make([]int, len(heights)+1)
+	computed := 0
+	start := func(idx int) int {
+		for ; computed < idx; computed++ {
+			starts[computed+1] = starts[computed] + heights[computed]
+			if rowLines {
+				starts[computed+1]++
+			}
+		}
+		return starts[idx]
+	}
+	// Get line of the cell content, where the row starts
+	offset := func(origin [2]int, idx int) int {
+		return start(idx) - start(origin[0]-first)
+	}
+	// Cells, spanning rows, grow their last row, ones ending above go first
+	sort.Slice(spanning, func(i int, j int) bool {
+		rowsI, _ := table.Data().getSpan(spanning[i][0], spanning[i][1])
+		rowsJ, _ := table.Data().getSpan(spanning[j][0], spanning[j][1])
+		return spanning[i][0]+rowsI < spanning[j][0]+rowsJ
+	})
+	for _, origin := range spanning {
+		rows, _ := table.Data().getSpan(origin[0], origin[1])
+		end := origin[0] + rows - 1 - first
+		if missing := len(lines[origin]) - offset(origin, end) - heights[end]; missing > 0 {
+			heights[end] += missing
+		}
+	}
+	line := func(origin [2]int, offset int) string {
+		if offset < len(lines[origin]) {
+			return lines[origin][offset]
 		}
+		return ""
 	}
 
-	return rendered.String()
-}
+	for idx, height := range heights {
+		row := first + idx
+		segments := table.rowSegments(row)
+		if !keep {
+			for _, segment := range segments {
+				if _, ok := lines[segment.origin]; !ok {
+					lines[segment.origin] = table.cellLines(row, segment.first, table.spanWidth(rowWidths, segment.first, segment.last))
+				}
+			}
+		}
 
-// Render row
-func (table *simpleTable) renderRow(cells []string) string {
-	var result string
-	if table.wrapText {
-		result = table.renderRowWrapped(cells)
-	} else {
-		result = table.renderRowSingle(cells)
+		for lineIdx := 0; lineIdx < height; lineIdx++ {
+			rendered := table.renderLine(segments, func(origin [2]int) string {
+				return line(origin, offset(origin, idx)+lineIdx)
+			})
+			if err := table.writeChunk(writer, rendered); err != nil {
+				return err
+			}
+		}
+		if idx < len(heights)-1 && rowLines {
+			rendered := table.renderBorder(_borderInner, row, row+1, func(origin [2]int) string {
+				return line(origin, offset(origin, idx+1)-1)
+			})
+			if err := table.writeChunk(writer, rendered); err != nil {
+				return err
+			}
+		}
+
+		// Content of the cells, which end in the row, is not needed anymore
+		for _, segment := range segments {
+			if rows, _ := table.Data().getSpan(segment.origin[0], segment.origin[1]); segment.origin[0]+rows-1 == row {
+				delete(lines, segment.origin)
+			}
+		}
 	}
 
-	return result
+	return nil
 }
 
-// Takes padded cells data and renders to the row with trimmed data
-func (table *simpleTable) renderRowSingle(cells []string) string {
+// Render line of the row segments with the content line of each segment
+func (table *SimpleTable) renderLine(segments []rowSegment, content func(origin [2]int) string) string {
 	rowWidths := table.getRowWidths()
+	borderSGR := table.style.borderSGR
 	var row string
-	for idx, cell := range cells {
+	for idx, segment := range segments {
 		if idx < 1 {
-			row += table.style.outer.VerticalLine()
+			row += table.paint(borderSGR, table.style.outer.VerticalLine())
 		}
-		row += table.renderCell(cell, rowWidths[idx], idx == 0, table.columnsAlign[idx])
-		if idx < len(cells)-1 {
-			row += table.style.inner.VerticalLine()
+		row += table.renderCell(content(segment.origin), table.spanWidth(rowWidths, segment.first, segment.last),
+			idx == 0, table.cellAlign(segment.origin[0], segment.origin[1]), table.cellTextStyle(segment.origin[0], segment.origin[1]))
+		if idx < len(segments)-1 {
+			row += table.paint(borderSGR, table.style.separator(segment.last).VerticalLine())
 		} else {
-			row += table.style.outer.VerticalLine()
+			row += table.paint(borderSGR, table.style.outer.VerticalLine())
 		}
 	}
 	return row
 }
 
-// Renders table as a string
-func (table *simpleTable) Render() string {
-	table.setDataMaxWidth()
-	render := make([]string, 0)
+// Get SGR sequence of the cell. Header row is -1, footer row is -2, header groups
+// are styled as the header. Cell style takes precedence over the row one, which
+// takes precedence over the column one. Footer style takes precedence over
+// the column one as well.
+func (table *SimpleTable) cellTextStyle(row int, column int) string {
+	if row == _rowHeader || groupLevel(row) > -1 {
+		return table.style.headerSGR
+	} else if row == _rowFooter && table.style.footerSGR != "" {
+		return table.style.footerSGR
+	} else if sgr, ok := table.cellTextStyles[[2]int{row, column}]; ok {
+		return sgr
+	} else if sgr, ok := table.rowTextStyles[row]; ok {
+		return sgr
+	}
+	return table.colTextStyles[column]
+}
+
+/*
+Renders table as a string. Empty string is returned, if the table is not valid.
+In COLOR_AUTO mode colors are enabled, if stdout is a terminal.
+*/
+func (table *SimpleTable) Render() string {
+	var rendered strings.Builder
+	table.colors = table.colorEnabled(os.Stdout)
+	table.render(&rendered) // strings.Builder never returns write errors
+	return rendered.String()
+}
+
+/*
+RenderTo writes the table to the writer, chunk by chunk, as borders and rows
+are rendered. Output is the same as of Render, but the whole table is never
+kept in memory. First write error stops rendering and is returned.
+The only difference is in COLOR_AUTO mode: colors are enabled, if the writer
+is a terminal, rather than stdout, so e.g. a file gets no colors, even if
+stdout is a terminal. Set COLOR_ALWAYS or COLOR_NEVER for the same output.
+*/
+func (table *SimpleTable) RenderTo(writer io.Writer) error {
+	return table.renderTo(writer, writer)
+}
+
+// Render table to the writer with colors, if they are enabled for the output
+func (table *SimpleTable) renderTo(writer io.Writer, output io.Writer) error {
+	table.colors = table.colorEnabled(output)
+	buff := bufio.NewWriter(writer)
+	if err := table.render(buff); err != nil {
+		return err
+	}
+	return buff.Flush()
+}
+
+// Render table to the writer
+func (table *SimpleTable) render(writer io.Writer) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+	table.style.resolveGlyphs()
+	table.fitColumns()
+	table.getRowWidths()
+
+	if table.title != "" && table.titleBorderWidth() == 0 {
+		if err := table.writeChunk(writer, table.renderSpanning(table.title, true)); err != nil {
+			return err
+		}
+	}
+
+	rows := table.Data().GetRowsNum()
+	footer := table.Data().HasFooter()
+	below := _rowNone // Row under the header
+	if rows > 0 {
+		below = 0
+	} else if footer {
+		below = _rowFooter
+	}
 
 	if len(*table.Data().GetHeader()) > 0 {
-		render = append(render, []string{
-			table.renderBorder(_borderTop),
-			table.renderRow(*table.Data().GetHeader()),
-			table.renderBorder(_borderHeader),
-		}...)
+		// Header group levels go above the header
+		levels := len(table.Data().GetHeaderGroups())
+		top := _rowHeader
+		if levels > 0 {
+			top = groupRow(0)
+		}
+		if err := table.writeChunk(writer, table.renderBorder(_borderTop, _rowNone, top, nil)); err != nil {
+			return err
+		}
+		for level := 0; level < levels; level++ {
+			lower := _rowHeader
+			if level < levels-1 {
+				lower = groupRow(level + 1)
+			}
+			if err := table.renderRows(writer, groupRow(level), groupRow(level)); err != nil {
+				return err
+			}
+			if err := table.writeChunk(writer, table.renderBorder(_borderGroup, groupRow(level), lower, nil)); err != nil {
+				return err
+			}
+		}
+
+		border := _borderHeader
+		if below == _rowNone {
+			// Header is all there is, so the table is closed right under it
+			border = _borderBottom
+		}
+		if err := table.renderRows(writer, _rowHeader, _rowHeader); err != nil {
+			return err
+		}
+		if err := table.writeChunk(writer, table.renderBorder(border, _rowHeader, below, nil)); err != nil {
+			return err
+		}
 	}
 
-	for _, row := range (*table.Data().GetData())[:len(*table.Data().GetData())-1] {
-		render = append(render, []string{
-			table.renderRow(row),
-			table.renderBorder(_borderInner),
-		}...)
+	// Rows, joined by row spans, are rendered at once
+	for first := 0; first < rows; {
+		last := first
+		for row := first; row <= last; row++ {
+			for column := range table.getRowWidths() {
+				if spanRows, _ := table.Data().getSpan(row, column); row+spanRows-1 > last {
+					last = row + spanRows - 1
+				}
+			}
+		}
+		if err := table.renderRows(writer, first, last); err != nil {
+			return err
+		}
+
+		var border string
+		if last < rows-1 {
+			border = table.renderBorder(_borderInner, last, last+1, nil)
+		} else if footer {
+			border = table.renderBorder(_borderFooter, last, _rowFooter, nil)
+		} else {
+			border = table.renderBorder(_borderBottom, last, _rowNone, nil)
+		}
+		if err := table.writeChunk(writer, border); err != nil {
+			return err
+		}
+		first = last + 1
 	}
-	render = append(render, []string{
-		table.renderRow((*table.Data().GetData())[len(*table.Data().GetData())-1]),
-		table.renderBorder(_borderBottom),
-	}...)
 
-	// Filter-out empty renders
-	var rendered strings.Builder
-	for _, chunk := range render {
-		if len(chunk) > 0 {
-			rendered.WriteString("\n" + chunk)
+	if footer {
+		if err := table.renderRows(writer, _rowFooter, _rowFooter); err != nil {
+			return err
+		}
+		if err := table.writeChunk(writer, table.renderBorder(_borderBottom, _rowFooter, _rowNone, nil)); err != nil {
+			return err
 		}
 	}
-	return rendered.String()
+
+	if table.caption != "" {
+		return table.writeChunk(writer, table.renderSpanning(table.caption, false))
+	}
+
+	return nil
+}
+
+// Write rendered chunk on a new line. Empty renders are filtered-out.
+func (table *SimpleTable) writeChunk(writer io.Writer, chunk string) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(writer, "\n"+chunk)
+	return err
 }
@@ -0,0 +1,188 @@
+package asciitable
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Table of ten columns with the number of cells
+func benchmarkTable(cells int) *SimpleTable {
+	data := NewTableData().SetHeader("ID", "Host", "Disk", "Size", "Used", "Free", "FS", "Mount", "State", "Notes")
+	for row := 0; row < cells/10; row++ {
+		data.AddRow(row, "alpha", "sda", "100G", "42G", "58G", "ext4", "/var", "online", "primary storage")
+	}
+	return NewSimpleTable(data, nil).SetWidth(120).SetColorMode(COLOR_NEVER)
+}
+
+// Render the table of the number of cells. Time per cell stays the same,
+// as long as rendering is linear.
+func benchmarkRender(b *testing.B, table *SimpleTable, cells int) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.Render()
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*cells), "ns/cell")
+}
+
+func BenchmarkRender10k(b *testing.B) {
+	benchmarkRender(b, benchmarkTable(10000), 10000)
+}
+
+func BenchmarkRender100k(b *testing.B) {
+	benchmarkRender(b, benchmarkTable(100000), 100000)
+}
+
+func BenchmarkRender1M(b *testing.B) {
+	benchmarkRender(b, benchmarkTable(1000000), 1000000)
+}
+
+// Host column is the same in all rows, so they all are merged into one block
+func BenchmarkRenderMerged100k(b *testing.B) {
+	table := benchmarkTable(100000)
+	table.Data().SetColMerge(true, 1)
+	benchmarkRender(b, table, 100000)
+}
+
+func TestRenderHeaderOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    *BorderStyle
+		width    int
+		expected string
+	}{
+		{"thin", NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE), 80, "\n┌─┬─┐\n│A│B│\n└─┴─┘"},
+		{"header line", NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetHeaderStyle(BORDER_DOUBLE).SetGlyphMode(GLYPHS_UNICODE), 80, "\n┌─┬─┐\n│A│B│\n└─┴─┘"},
+		{"ascii", NewBorderStyle(BORDER_ASCII, BORDER_ASCII), 80, "\n+-+-+\n|A|B|\n+-+-+"},
+		{"zero width", NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE), 0, "\n┌┬┐\n│││\n└┴┘"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewSimpleTable(NewTableData().SetHeader("A", "B"), test.style).SetWidth(test.width).SetColorMode(COLOR_NEVER)
+			if rendered := table.Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestRenderNarrowWidth(t *testing.T) {
+	for _, width := range []int{0, 1, 3, 8} {
+		data := NewTableData().SetHeader("A", "B").AddRow("hello", "world")
+		rendered := NewSimpleTable(data, NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN)).SetWidth(width).SetColorMode(COLOR_NEVER).Render()
+		if lines := strings.Count(rendered, "\n"); lines != 4 {
+			t.Errorf("SetWidth(%d): Render() = %q, expected 4 lines", width, rendered)
+		}
+	}
+}
+
+// Writer, failing after the number of bytes
+type failingWriter struct {
+	left int
+}
+
+var errWrite = errors.New("disk full")
+
+func (writer *failingWriter) Write(data []byte) (int, error) {
+	if len(data) > writer.left {
+		written := writer.left
+		writer.left = 0
+		return written, errWrite
+	}
+	writer.left -= len(data)
+	return len(data), nil
+}
+
+func TestRenderTo(t *testing.T) {
+	data := NewTableData().SetHeader("Host", "State").
+		AddRow("alpha", "\x1b[32monline\x1b[0m").
+		AddRow("beta", "\x1b[31moffline\x1b[0m").
+		SetFooter("Total").SetFooterAggregate(AGGREGATE_COUNT, 1)
+	for _, mode := range []int{COLOR_NEVER, COLOR_ALWAYS} {
+		table := NewSimpleTable(data, NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)).
+			SetTitle("Hosts").SetCaption("Last hour").SetColorMode(mode)
+		var output bytes.Buffer
+		if err := table.RenderTo(&output); err != nil {
+			t.Fatal(err)
+		}
+		if rendered := table.Render(); output.String() != rendered {
+			t.Errorf("color mode %d: RenderTo() = %q, Render() = %q", mode, output.String(), rendered)
+		}
+	}
+}
+
+func TestRenderToError(t *testing.T) {
+	for _, cells := range []int{10, 10000} {
+		if err := benchmarkTable(cells).RenderTo(&failingWriter{left: 100}); !errors.Is(err, errWrite) {
+			t.Errorf("%d cells: RenderTo() = %v, expected %v", cells, err, errWrite)
+		}
+	}
+	if err := NewSimpleTable(NewTableData(), nil).RenderTo(&bytes.Buffer{}); !errors.Is(err, ErrNoData) {
+		t.Errorf("RenderTo() of empty table = %v, expected %v", err, ErrNoData)
+	}
+}
+
+func TestRenderStyleChange(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("Host", "Disks of the host", "").
+			AddRow("alpha", "sda", "100G").AddRow("beta", "sdb", "2T").
+			SetCellSpan(-1, 1, 1, 2).SetCellSpan(0, 0, 2, 1)
+	}
+	style := func() *BorderStyle {
+		return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)
+	}
+	tests := []struct {
+		name   string
+		change func(style *BorderStyle)
+	}{
+		{"grid", func(style *BorderStyle) { style.SetGridVisible(false) }},
+		{"border", func(style *BorderStyle) { style.SetBorderVisible(false) }},
+		{"column separator", func(style *BorderStyle) { style.SetColSeparator(BORDER_NONE, 0) }},
+		{"row separator", func(style *BorderStyle) { style.SetRowSeparator(BORDER_DOUBLE) }},
+		{"header", func(style *BorderStyle) { style.SetHeaderStyle(BORDER_SINGLE_THICK) }},
+		{"glyphs", func(style *BorderStyle) { style.SetGlyphMode(GLYPHS_ASCII) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed := style()
+			table := NewSimpleTable(data(), changed).SetColorMode(COLOR_NEVER)
+			table.Render() // Layout is cached
+			test.change(changed)
+
+			fresh := style()
+			test.change(fresh)
+			expected := NewSimpleTable(data(), fresh).SetColorMode(COLOR_NEVER).Render()
+			if rendered := table.Render(); rendered != expected {
+				t.Errorf("Render() = %q, expected %q", rendered, expected)
+			}
+		})
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		width     int
+		direction int
+		expected  string
+	}{
+		{"left", "ab", 5, ALIGN_LEFT, "ab   "},
+		{"right", "ab", 5, ALIGN_RIGHT, "   ab"},
+		{"center", "ab", 5, ALIGN_CENTER, " ab  "},
+		{"wider than width", "abcdef", 3, ALIGN_RIGHT, "abcdef"},
+		{"wide characters", "日本", 6, ALIGN_RIGHT, "  日本"},
+		{"color is reset", "\x1b[31mab\x1b[0m", 4, ALIGN_LEFT, "\x1b[31mab\x1b[0m  \x1b[0m"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewSimpleTable(nil, nil)
+			table.colors = true // Enabled by the render
+			if aligned := table.align(test.data, test.width, test.direction); aligned != test.expected {
+				t.Errorf("align(%q, %d) = %q, expected %q", test.data, test.width, aligned, test.expected)
+			}
+		})
+	}
+}
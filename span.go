@@ -0,0 +1,246 @@
+package asciitable
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Row indexes of the header and footer in spans and text styles. Internal use.
+const (
+	_rowHeader = -1
+	_rowFooter = -2
+	_rowNone   = -3 // No row, e.g. above the top border
+)
+
+/*
+Set span of the cell, so it is merged with the cells right of it and below it.
+Data of the merged cells is not rendered. Row -1 is the header and row -2
+is the footer, which span only columns. Header groups span their columns
+already. Span of one row and one column removes it. Text and HTML tables
+render spans, Markdown has no spans and ignores them.
+*/
+func (tableData *TableData) SetCellSpan(row int, column int, rows int, columns int) *TableData {
+	if rows < 1 || columns < 1 {
+		tableData.setError(fmt.Errorf("SetCellSpan: span %dx%d: %w", rows, columns, ErrInvalidOption))
+		return tableData
+	} else if row < _rowFooter {
+		tableData.setError(fmt.Errorf("SetCellSpan: row %d: %w", row, ErrRowOutOfRange))
+		return tableData
+	} else if column < 0 {
+		tableData.setError(fmt.Errorf("SetCellSpan: column %d: %w", column, ErrColumnOutOfRange))
+		return tableData
+	}
+
+	if tableData.spans == nil {
+		tableData.spans = make(map[[2]int][2]int)
+	}
+	if rows == 1 && columns == 1 {
+		delete(tableData.spans, [2]int{row, column})
+	} else {
+		tableData.spans[[2]int{row, column}] = [2]int{rows, columns}
+	}
+	tableData.revision++
+
+	return tableData
+}
+
+/*
+Set whether vertically adjacent cells with the same value are merged into
+one tall cell in the columns, e.g. host name over its disks. Empty cells and
+cells, covered by set spans, are never merged. Values are merged only within
+the values merged in the columns on the left, so a repeated value does not
+span two hosts. If columns contains only one value and it is -1, then
+the setting applies to all columns at once.
+*/
+func (tableData *TableData) SetColMerge(merge bool, columns ...int) *TableData {
+	if len(columns) == 1 && columns[0] == -1 {
+		tableData.merges = map[int]bool{-1: merge}
+	} else {
+		for _, column := range columns {
+			if column < 0 {
+				tableData.setError(fmt.Errorf("SetColMerge: column %d: %w", column, ErrColumnOutOfRange))
+				return tableData
+			}
+		}
+		if tableData.merges == nil {
+			tableData.merges = make(map[int]bool)
+		}
+		for _, column := range columns {
+			tableData.merges[column] = merge
+		}
+	}
+	tableData.revision++
+
+	return tableData
+}
+
+// Check if repeated values are merged in the column
+func (tableData *TableData) isMerged(column int) bool {
+	if merge, ok := tableData.merges[column]; ok {
+		return merge
+	}
+	return tableData.merges[-1]
+}
+
+// Get span of the cell in rows and columns
+func (tableData *TableData) getSpan(row int, column int) (int, int) {
+	tableData.refreshSpans()
+	if span, ok := tableData.cellSpans[[2]int{row, column}]; ok {
+		return span[0], span[1]
+	}
+	return 1, 1
+}
+
+// Get the cell, which covers the position. Cells without spans cover only themselves.
+func (tableData *TableData) cellOrigin(row int, column int) [2]int {
+	tableData.refreshSpans()
+	if origin, ok := tableData.origins[[2]int{row, column}]; ok {
+		return origin
+	}
+	return [2]int{row, column}
+}
+
+// Compute cell spans and their origins again, if data has changed
+func (tableData *TableData) refreshSpans() {
+	if tableData.originsRevision == tableData.revision && tableData.origins != nil {
+		return
+	}
+	tableData.cellSpans = tableData.mergeSpans()
+	tableData.origins, _ = spanOrigins(tableData.cellSpans)
+	tableData.originsRevision = tableData.revision
+}
+
+// Get set spans along with spans of merged repeated values
+func (tableData *TableData) mergeSpans() map[[2]int][2]int {
+	spans := make(map[[2]int][2]int, len(tableData.spans))
+	for cell, span := range tableData.spans {
+		spans[cell] = span
+	}
+	if len(tableData.merges) == 0 {
+		return spans
+	}
+
+	covered, _ := spanOrigins(tableData.spans)
+	value := func(row int, column int) string {
+		if _, ok := covered[[2]int{row, column}]; ok || column >= len(tableData.data[row]) {
+			return ""
+		}
+		return tableData.data[row][column]
+	}
+
+	// Rows, where merged values of the columns on the left end
+	breaks := make(map[int]bool)
+	for column := 0; column < tableData.GetColsNum(); column++ {
+		if !tableData.isMerged(column) {
+			continue
+		}
+		starts := make(map[int]bool)
+		for first := 0; first < tableData.GetRowsNum(); {
+			last := first
+			if data := value(first, column); data != "" {
+				for last+1 < tableData.GetRowsNum() && !breaks[last+1] && value(last+1, column) == data {
+					last++
+				}
+			}
+			if last > first {
+				spans[[2]int{first, column}] = [2]int{last - first + 1, 1}
+			}
+			starts[first] = true
+			first = last + 1
+		}
+		for row := range starts {
+			breaks[row] = true
+		}
+	}
+
+	return spans
+}
+
+// Get origins of the cells, covered by spans. Overlapping spans are errors.
+func spanOrigins(spans map[[2]int][2]int) (map[[2]int][2]int, error) {
+	cells := make([][2]int, 0, len(spans))
+	for cell := range spans {
+		cells = append(cells, cell)
+	}
+	sort.Slice(cells, func(i int, j int) bool {
+		return cells[i][0] < cells[j][0] || cells[i][0] == cells[j][0] && cells[i][1] < cells[j][1]
+	})
+
+	origins := make(map[[2]int][2]int)
+	for _, cell := range cells {
+		rows, columns := spans[cell][0], spans[cell][1]
+		for row := cell[0]; row < cell[0]+rows; row++ {
+			for column := cell[1]; column < cell[1]+columns; column++ {
+				covered := [2]int{row, column}
+				if origin, ok := origins[covered]; ok {
+					return origins, fmt.Errorf("span of cell %d:%d overlaps span of cell %d:%d: %w", cell[0], cell[1], origin[0], origin[1], ErrInvalidOption)
+				}
+				if _, ok := spans[covered]; ok && covered != cell {
+					return origins, fmt.Errorf("span of cell %d:%d overlaps span of cell %d:%d: %w", cell[0], cell[1], row, column, ErrInvalidOption)
+				}
+				origins[covered] = cell
+			}
+		}
+	}
+
+	return origins, nil
+}
+
+// Check that spans fit into the table of the number of columns and do not overlap
+func (tableData *TableData) validateSpans(colsNum int) error {
+	if len(tableData.groups) > 0 && len(tableData.header) == 0 {
+		return fmt.Errorf("header groups are set without header: %w", ErrNoData)
+	}
+	for cell, span := range tableData.spans {
+		row, column := cell[0], cell[1]
+		switch {
+		case row == _rowHeader && len(tableData.header) == 0, row == _rowFooter && !tableData.HasFooter(),
+			row >= tableData.GetRowsNum():
+			return fmt.Errorf("span of cell %d:%d: row %d: %w", row, column, row, ErrRowOutOfRange)
+		case row < 0 && span[0] > 1:
+			return fmt.Errorf("span of cell %d:%d: header and footer span only columns: %w", row, column, ErrInvalidOption)
+		case row+span[0] > tableData.GetRowsNum():
+			return fmt.Errorf("span of cell %d:%d: row %d: %w", row, column, row+span[0]-1, ErrRowOutOfRange)
+		case column+span[1] > colsNum:
+			return fmt.Errorf("span of cell %d:%d: column %d: %w", row, column, column+span[1]-1, ErrColumnOutOfRange)
+		}
+	}
+
+	_, err := spanOrigins(tableData.spans)
+	return err
+}
+
+/*
+Drop arms of the junction glyph, where cells are merged: up, right, down, left.
+Junction, which is left with the horizontal or vertical arms only, becomes
+the given line, and a space, if there are no arms left. Glyphs, which are
+not box-drawing ones, e.g. ASCII "+", are kept otherwise, so arms, which
+the junction does not have, e.g. up arm on the top border, must be dropped too.
+*/
+func mergeJunction(glyph string, drop [4]bool, horizontal string, vertical string) string {
+	if glyph == "" || drop == [4]bool{} {
+		return glyph
+	}
+
+	arms, known := _junctionArms[glyph]
+	if !known {
+		arms = [4]int{_lineThin, _lineThin, _lineThin, _lineThin}
+	}
+	for idx := range arms {
+		if drop[idx] {
+			arms[idx] = _lineNone
+		}
+	}
+
+	switch {
+	case arms == [4]int{}:
+		return " "
+	case arms[0] == _lineNone && arms[2] == _lineNone:
+		return horizontal
+	case arms[1] == _lineNone && arms[3] == _lineNone:
+		return vertical
+	case !known:
+		return glyph
+	}
+	return junction(arms[0], arms[1], arms[2], arms[3])
+}
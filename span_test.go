@@ -0,0 +1,172 @@
+package asciitable
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeJunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		glyph    string
+		drop     [4]bool
+		expected string
+	}{
+		{"nothing dropped", "┼", [4]bool{}, "┼"},
+		{"up", "┼", [4]bool{true, false, false, false}, "┬"},
+		{"left", "┼", [4]bool{false, false, false, true}, "├"},
+		{"up and down", "┼", [4]bool{true, false, true, false}, "─"},
+		{"left and right", "╪", [4]bool{false, true, false, true}, "│"},
+		{"all", "┼", [4]bool{true, true, true, true}, " "},
+		{"double", "╬", [4]bool{true, false, false, false}, "╦"},
+		{"ascii", "+", [4]bool{true, false, false, false}, "+"},
+		{"ascii up and down", "+", [4]bool{true, false, true, false}, "─"},
+		{"ascii left and right", "+", [4]bool{false, true, false, true}, "│"},
+		{"empty", "", [4]bool{true, false, false, false}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if glyph := mergeJunction(test.glyph, test.drop, "─", "│"); glyph != test.expected {
+				t.Errorf("mergeJunction(%q, %v) = %q, expected %q", test.glyph, test.drop, glyph, test.expected)
+			}
+		})
+	}
+}
+
+func TestCellOrigin(t *testing.T) {
+	data := NewTableData().SetHeader("A", "B", "C").
+		SetData([][]interface{}{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}).
+		SetCellSpan(0, 0, 2, 2).SetCellSpan(-1, 1, 1, 2)
+	tests := []struct {
+		row, column int
+		origin      [2]int
+	}{
+		{0, 0, [2]int{0, 0}},
+		{0, 1, [2]int{0, 0}},
+		{1, 0, [2]int{0, 0}},
+		{1, 1, [2]int{0, 0}},
+		{1, 2, [2]int{1, 2}},
+		{2, 0, [2]int{2, 0}},
+		{_rowHeader, 2, [2]int{_rowHeader, 1}},
+		{_rowHeader, 0, [2]int{_rowHeader, 0}},
+	}
+	for _, test := range tests {
+		if origin := data.cellOrigin(test.row, test.column); origin != test.origin {
+			t.Errorf("cellOrigin(%d, %d) = %v, expected %v", test.row, test.column, origin, test.origin)
+		}
+	}
+
+	// Span of one cell removes it
+	data.SetCellSpan(0, 0, 1, 1)
+	if origin := data.cellOrigin(1, 1); origin != [2]int{1, 1} {
+		t.Errorf("cellOrigin(1, 1) = %v after the span is removed", origin)
+	}
+}
+
+func TestSpanErrors(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("A", "B").AddRow(1, 2).AddRow(3, 4)
+	}
+	tests := []struct {
+		name string
+		data *TableData
+		err  error
+	}{
+		{"zero rows", data().SetCellSpan(0, 0, 0, 1), ErrInvalidOption},
+		{"negative column", data().SetCellSpan(0, -1, 1, 2), ErrColumnOutOfRange},
+		{"row above header", data().SetCellSpan(-3, 0, 1, 2), ErrRowOutOfRange},
+		{"beyond columns", data().SetCellSpan(0, 0, 1, 3), ErrColumnOutOfRange},
+		{"beyond rows", data().SetCellSpan(1, 0, 2, 1), ErrRowOutOfRange},
+		{"header spans rows", data().SetCellSpan(-1, 0, 2, 1), ErrInvalidOption},
+		{"footer without footer", data().SetCellSpan(-2, 0, 1, 2), ErrRowOutOfRange},
+		{"overlap", data().SetCellSpan(0, 0, 2, 1).SetCellSpan(1, 0, 1, 2), ErrInvalidOption},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := NewSimpleTable(test.data, nil).Validate(); !errors.Is(err, test.err) {
+				t.Errorf("Validate() = %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestRenderSpans(t *testing.T) {
+	data := NewTableData().SetHeader("A", "B", "C").
+		SetData([][]interface{}{{"ab", "", "c1"}, {"", "", "c2"}, {"a3", "b3", "c3"}}).
+		SetCellSpan(0, 0, 2, 2).SetCellSpan(-1, 1, 1, 2)
+	tests := []struct {
+		name     string
+		style    *BorderStyle
+		expected string
+	}{
+		{"unicode", NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetHeaderStyle(BORDER_DOUBLE).SetGlyphMode(GLYPHS_UNICODE),
+			"\n┌──┬─────┐\n│A │B    │\n╞══╧══╤══╡\n│ab   │c1│\n│     ├──┤\n│     │c2│\n├──┬──┼──┤\n│a3│b3│c3│\n└──┴──┴──┘"},
+		{"ascii", NewBorderStyle(BORDER_ASCII, BORDER_ASCII),
+			"\n+--+-----+\n|A |B    |\n|ab   |c1|\n|     +--+\n|     |c2|\n+--+--+--+\n|a3|b3|c3|\n+--+--+--+"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rendered := NewSimpleTable(data, test.style).SetColorMode(COLOR_NEVER).Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestRenderMerges(t *testing.T) {
+	data := func() *TableData {
+		return NewTableData().SetHeader("Host", "Disk", "FS", "Size").
+			SetData([][]interface{}{
+				{"alpha", "sda", "ext4", "100G"},
+				{"alpha", "sdb", "ext4", "2T"},
+				{"alpha", "sdc", "xfs", "1T"},
+				{"beta", "sda", "xfs", "500G"},
+				{"beta", "sdb", "ext4", "1T"},
+				{"gamma", "sda", "ext4", "1T"},
+			})
+	}
+	thin := func() *BorderStyle {
+		return NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)
+	}
+	tests := []struct {
+		name     string
+		data     *TableData
+		style    *BorderStyle
+		expected string
+	}{
+		{"first column", data().SetColMerge(true, 0), thin(),
+			"\n┌─────┬────┬────┬────┐\n│Host │Disk│FS  │Size│\n│alpha│sda │ext4│100G│\n│     ├────┼────┼────┤\n│     │sdb │ext4│2T  │\n│     ├────┼────┼────┤\n│     │sdc │xfs │1T  │\n├─────┼────┼────┼────┤\n│beta │sda │xfs │500G│\n│     ├────┼────┼────┤\n│     │sdb │ext4│1T  │\n├─────┼────┼────┼────┤\n│gamma│sda │ext4│1T  │\n└─────┴────┴────┴────┘"},
+		{"runs break with the left column", data().SetColMerge(true, 0, 2), NewBorderStyle(BORDER_DOUBLE, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE),
+			"\n╔═════╤════╤════╤════╗\n║Host │Disk│FS  │Size║\n║alpha│sda │ext4│100G║\n║     ├────┤    ├────╢\n║     │sdb │    │2T  ║\n║     ├────┼────┼────╢\n║     │sdc │xfs │1T  ║\n╟─────┼────┼────┼────╢\n║beta │sda │xfs │500G║\n║     ├────┼────┼────╢\n║     │sdb │ext4│1T  ║\n╟─────┼────┼────┼────╢\n║gamma│sda │ext4│1T  ║\n╚═════╧════╧════╧════╝"},
+		{"ascii", data().SetColMerge(true, 2), NewBorderStyle(BORDER_ASCII, BORDER_ASCII),
+			"\n+-----+----+----+----+\n|Host |Disk|FS  |Size|\n|alpha|sda |ext4|100G|\n+-----+----+    +----+\n|alpha|sdb |    |2T  |\n+-----+----+----+----+\n|alpha|sdc |xfs |1T  |\n+-----+----+    +----+\n|beta |sda |    |500G|\n+-----+----+----+----+\n|beta |sdb |ext4|1T  |\n+-----+----+    +----+\n|gamma|sda |    |1T  |\n+-----+----+----+----+"},
+		{"without row lines", data().SetColMerge(true, 0), thin().SetRowSeparator(BORDER_NONE),
+			"\n┌─────┬────┬────┬────┐\n│Host │Disk│FS  │Size│\n│alpha│sda │ext4│100G│\n│     │sdb │ext4│2T  │\n│     │sdc │xfs │1T  │\n│beta │sda │xfs │500G│\n│     │sdb │ext4│1T  │\n│gamma│sda │ext4│1T  │\n└─────┴────┴────┴────┘"},
+		{"all columns with explicit span", data().SetColMerge(true, -1).SetColMerge(false, 3).SetCellSpan(1, 2, 1, 2), thin(),
+			"\n┌─────┬────┬────┬────┐\n│Host │Disk│FS  │Size│\n│alpha│sda │ext4│100G│\n│     ├────┼────┴────┤\n│     │sdb │ext4     │\n│     ├────┼────┬────┤\n│     │sdc │xfs │1T  │\n├─────┼────┼────┼────┤\n│beta │sda │xfs │500G│\n│     ├────┼────┼────┤\n│     │sdb │ext4│1T  │\n├─────┼────┼────┼────┤\n│gamma│sda │ext4│1T  │\n└─────┴────┴────┴────┘"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rendered := NewSimpleTable(test.data, test.style).SetColorMode(COLOR_NEVER).Render(); rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestRenderMergeWrapped(t *testing.T) {
+	data := NewTableData().SetHeader("Host", "Disk").SetData([][]interface{}{
+		{"a very long host name that wraps", "sda"}, {"a very long host name that wraps", "sdb"}, {"b", "sda"},
+	}).SetColMerge(true, 0)
+	expected := "\n┌──────────┬────┐\n│Host      │Disk│\n│a very    │sda │\n│long host ├────┤\n│name that │sdb │\n│wraps     │    │\n├──────────┼────┤\n│b         │sda │\n└──────────┴────┘"
+	table := NewSimpleTable(data, NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)).SetTextWrap(true).SetColWidth(10, 0).SetColorMode(COLOR_NEVER)
+	if rendered := table.Render(); rendered != expected {
+		t.Errorf("Render() = %q, expected %q", rendered, expected)
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	if err := NewTableData().SetColMerge(true, -2).Err(); !errors.Is(err, ErrColumnOutOfRange) {
+		t.Errorf("SetColMerge(true, -2) = %v, expected %v", err, ErrColumnOutOfRange)
+	}
+}
@@ -0,0 +1,234 @@
+package asciitable
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const _structTag = "asciitable"
+
+// Column, derived from a struct field
+type structColumn struct {
+	name  string
+	title string
+	index []int // Field index path, including embedded structs
+	depth int
+	align int
+	width int
+	order int
+	fixed bool // Order is set explicitly
+}
+
+/*
+NewTableDataFromStructs constructs table data from a slice (or array) of structs
+or pointers to structs. Each exported field becomes a column, fields of embedded
+structs are promoted as in Go. Columns are configured with a struct tag:
+
+	Size int `asciitable:"Size (MB),align=right,width=10,order=2"`
+
+The first tag value is the column title (field name, if empty). Options are
+align (left, center, right), width, order and omit, which skips the field,
+just as the "-" tag does. Columns with order go first, the rest follow in fields order.
+Input, which is not a slice of structs, and invalid tags are reported with errors,
+wrapping ErrInvalidOption.
+*/
+func NewTableDataFromStructs(slice interface{}) (*TableData, error) {
+	value := reflect.ValueOf(slice)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected slice of structs, got %T: %w", slice, ErrInvalidOption)
+	}
+
+	elemType := value.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected slice of structs, got %T: %w", slice, ErrInvalidOption)
+	}
+
+	columns, err := structColumns(elemType, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	columns = promoteColumns(columns)
+	sort.SliceStable(columns, func(i, j int) bool {
+		if columns[i].fixed && columns[j].fixed {
+			return columns[i].order < columns[j].order
+		}
+		return columns[i].fixed && !columns[j].fixed
+	})
+
+	tableData := NewTableData()
+	titles := make([]string, len(columns))
+	tableData.align = make([]int, len(columns))
+	tableData.width = make([]int, len(columns))
+	for idx, column := range columns {
+		titles[idx] = column.title
+		tableData.align[idx] = column.align
+		tableData.width[idx] = column.width
+	}
+	tableData.SetHeader(titles...)
+
+	for idx := 0; idx < value.Len(); idx++ {
+		item := value.Index(idx)
+		row := make([]interface{}, len(columns))
+		for cidx, column := range columns {
+			row[cidx] = structFieldValue(item, column.index)
+		}
+		tableData.AddRow(row...)
+	}
+
+	return tableData, nil
+}
+
+// Collect columns of the struct type, descending into embedded structs
+func structColumns(structType reflect.Type, index []int, depth int) ([]structColumn, error) {
+	columns := make([]structColumn, 0)
+	for idx := 0; idx < structType.NumField(); idx++ {
+		field := structType.Field(idx)
+		tag, tagged := field.Tag.Lookup(_structTag)
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), idx)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// Embedded struct without own title is flattened
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && (!tagged || strings.Split(tag, ",")[0] == "") {
+			embedded, err := structColumns(fieldType, fieldIndex, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+
+		if field.PkgPath != "" { // Unexported
+			continue
+		}
+
+		column := structColumn{name: field.Name, title: field.Name, index: fieldIndex, depth: depth, align: ALIGN_LEFT}
+		omit, err := column.parseTag(tag)
+		if err != nil {
+			fieldName := field.Name
+			if structType.Name() != "" {
+				fieldName = structType.Name() + "." + fieldName
+			}
+			return nil, fmt.Errorf("field %s: %w", fieldName, err)
+		}
+		if !omit {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// Apply struct tag options to the column. Returns true, if the field is omitted.
+func (column *structColumn) parseTag(tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	options := strings.Split(tag, ",")
+	if options[0] != "" {
+		column.title = options[0]
+	}
+	for _, option := range options[1:] {
+		name, value := option, ""
+		if pos := strings.Index(option, "="); pos > -1 {
+			name, value = option[:pos], option[pos+1:]
+		}
+
+		var err error
+		switch strings.TrimSpace(name) {
+		case "omit":
+			return true, nil
+		case "align":
+			switch value {
+			case "left":
+				column.align = ALIGN_LEFT
+			case "center":
+				column.align = ALIGN_CENTER
+			case "right":
+				column.align = ALIGN_RIGHT
+			default:
+				return false, fmt.Errorf("unknown align %q: %w", value, ErrInvalidOption)
+			}
+		case "width":
+			column.width, err = strconv.Atoi(value)
+			if err != nil || column.width < 0 {
+				return false, fmt.Errorf("invalid width %q: %w", value, ErrInvalidOption)
+			}
+		case "order":
+			column.order, err = strconv.Atoi(value)
+			if err != nil {
+				return false, fmt.Errorf("invalid order %q: %w", value, ErrInvalidOption)
+			}
+			column.fixed = true
+		default:
+			return false, fmt.Errorf("unknown tag option %q: %w", name, ErrInvalidOption)
+		}
+	}
+
+	return false, nil
+}
+
+// Resolve columns with the same field name as Go resolves promoted fields:
+// the shallowest one wins, while ambiguous ones at the same depth are dropped.
+func promoteColumns(columns []structColumn) []structColumn {
+	depths := make(map[string][]int)
+	for _, column := range columns {
+		depths[column.name] = append(depths[column.name], column.depth)
+	}
+
+	promoted := make([]structColumn, 0, len(columns))
+	for _, column := range columns {
+		shallower, same := false, 0
+		for _, depth := range depths[column.name] {
+			if depth < column.depth {
+				shallower = true
+			} else if depth == column.depth {
+				same++
+			}
+		}
+		if !shallower && same == 1 {
+			promoted = append(promoted, column)
+		}
+	}
+
+	return promoted
+}
+
+// Get value of the field by its index path. Nil pointers on the way result in an empty value.
+func structFieldValue(value reflect.Value, index []int) interface{} {
+	for _, idx := range index {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		value = value.Field(idx)
+	}
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	// Fields, promoted from unexported embedded structs, are read-only
+	if !value.CanInterface() {
+		return fmt.Sprint(value)
+	}
+	return value.Interface()
+}
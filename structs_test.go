@@ -0,0 +1,119 @@
+package asciitable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type structDisk struct {
+	Device string `asciitable:"Device,order=1"`
+	Size   int    `asciitable:"Size (GB),align=right,width=10"`
+	Serial string `asciitable:"-"`
+	Notes  string `asciitable:",omit"`
+	mount  string
+}
+
+type structLocation struct {
+	Rack string
+	Name string // Shadowed by the host name
+}
+
+type structHost struct {
+	Name string
+	*structLocation
+	Load  *float64
+	State interface{}
+}
+
+type structLabel struct {
+	Text string
+}
+
+type structTitled struct {
+	Name        string
+	structLabel `asciitable:"Label"`
+}
+
+func TestNewTableDataFromStructs(t *testing.T) {
+	load := 0.5
+	tests := []struct {
+		name   string
+		input  interface{}
+		header []string
+		data   [][]string
+		align  []int
+		width  []int
+	}{
+		{"tags", []structDisk{{"sda", 100, "S1", "boot", "/"}, {"sdb", 2000, "S2", "", ""}},
+			[]string{"Device", "Size (GB)"}, [][]string{{"sda", "100"}, {"sdb", "2000"}},
+			[]int{ALIGN_LEFT, ALIGN_RIGHT}, []int{0, 10}},
+		{"pointer elements", []*structDisk{{Device: "sda", Size: 1}, nil},
+			[]string{"Device", "Size (GB)"}, [][]string{{"sda", "1"}, {"", ""}},
+			[]int{ALIGN_LEFT, ALIGN_RIGHT}, []int{0, 10}},
+		{"array", [1]structDisk{{Device: "sda", Size: 1}},
+			[]string{"Device", "Size (GB)"}, [][]string{{"sda", "1"}},
+			[]int{ALIGN_LEFT, ALIGN_RIGHT}, []int{0, 10}},
+		{"embedded", []structHost{
+			{"alpha", &structLocation{"r1", "dc1"}, &load, "online"},
+			{"beta", nil, nil, nil},
+		}, []string{"Name", "Rack", "Load", "State"}, [][]string{{"alpha", "r1", "0.5", "online"}, {"beta", "", "", ""}},
+			[]int{ALIGN_LEFT, ALIGN_LEFT, ALIGN_LEFT, ALIGN_LEFT}, []int{0, 0, 0, 0}},
+		{"unexported embedded with title", []structTitled{{"alpha", structLabel{"db"}}},
+			[]string{"Name"}, [][]string{{"alpha"}},
+			[]int{ALIGN_LEFT}, []int{0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tableData, err := NewTableDataFromStructs(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header := *tableData.GetHeader(); !reflect.DeepEqual(header, test.header) {
+				t.Errorf("header = %q, expected %q", header, test.header)
+			}
+			if data := *tableData.GetData(); !reflect.DeepEqual(data, test.data) {
+				t.Errorf("data = %q, expected %q", data, test.data)
+			}
+			for column, align := range test.align {
+				if hint := tableData.getColAlignHint(column); hint != align {
+					t.Errorf("align of column %d = %d, expected %d", column, hint, align)
+				}
+			}
+			if !reflect.DeepEqual(tableData.width, test.width) {
+				t.Errorf("width = %v, expected %v", tableData.width, test.width)
+			}
+		})
+	}
+}
+
+func TestNewTableDataFromStructsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"struct", structDisk{}},
+		{"nil", nil},
+		{"slice of strings", []string{"a"}},
+		{"slice of pointers to ints", []*int{}},
+		{"unknown align", []struct {
+			A string `asciitable:",align=top"`
+		}{}},
+		{"invalid width", []struct {
+			A string `asciitable:",width=-1"`
+		}{}},
+		{"invalid order", []struct {
+			A string `asciitable:",order=first"`
+		}{}},
+		{"unknown option", []struct {
+			A string `asciitable:",bold"`
+		}{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewTableDataFromStructs(test.input); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("error = %v, expected %v", err, ErrInvalidOption)
+			}
+		})
+	}
+}
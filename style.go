@@ -1,37 +1,75 @@
 package asciitable
 
-type borderOuter struct {
-	VERTICAL_LINE   string
-	HORISONTAL_LINE string
-	LEFT_TOP        string
-	LEFT_BOTTOM     string
-	RIGHT_TOP       string
-	RIGHT_BOTTOM    string
-	IS_VISIBLE      bool
+import (
+	"fmt"
+)
+
+// BorderOuter defines glyphs of the outer border of the table.
+type BorderOuter struct {
+	VERTICAL_LINE   string `json:"vertical_line,omitempty"`
+	HORISONTAL_LINE string `json:"horisontal_line,omitempty"`
+	LEFT_TOP        string `json:"left_top,omitempty"`
+	LEFT_BOTTOM     string `json:"left_bottom,omitempty"`
+	RIGHT_TOP       string `json:"right_top,omitempty"`
+	RIGHT_BOTTOM    string `json:"right_bottom,omitempty"`
+	IS_VISIBLE      bool   `json:"-"`
 	style           int
 }
 
-type borderInner struct {
-	VERTICAL_LINE     string
-	HORISONTAL_LINE   string
-	LEFT_MIDDLE       string
-	CENTER_TOP        string
-	CENTER_BOTTOM     string
-	CENTER_MIDDLE     string
-	RIGHT_MIDDLE      string
-	HEADER_LEFT       string
-	HEADER_MIDDLE     string
-	HEADER_RIGHT      string
-	HEADER            string
-	HEADER_IS_VISIBLE bool
-	IS_VISIBLE        bool
+/*
+BorderInner defines glyphs of the grid inside the table. HEADER glyphs
+draw the line under the header, FOOTER glyphs draw the line above the footer,
+they are optional.
+*/
+type BorderInner struct {
+	VERTICAL_LINE     string `json:"vertical_line,omitempty"`
+	HORISONTAL_LINE   string `json:"horisontal_line,omitempty"`
+	LEFT_MIDDLE       string `json:"left_middle,omitempty"`
+	CENTER_TOP        string `json:"center_top,omitempty"`
+	CENTER_BOTTOM     string `json:"center_bottom,omitempty"`
+	CENTER_MIDDLE     string `json:"center_middle,omitempty"`
+	RIGHT_MIDDLE      string `json:"right_middle,omitempty"`
+	HEADER_LEFT       string `json:"header_left,omitempty"`
+	HEADER_MIDDLE     string `json:"header_middle,omitempty"`
+	HEADER_RIGHT      string `json:"header_right,omitempty"`
+	HEADER            string `json:"header,omitempty"`
+	FOOTER_LEFT       string `json:"footer_left,omitempty"`
+	FOOTER_MIDDLE     string `json:"footer_middle,omitempty"`
+	FOOTER_RIGHT      string `json:"footer_right,omitempty"`
+	FOOTER            string `json:"footer,omitempty"`
+	HEADER_IS_VISIBLE bool   `json:"-"`
+	IS_VISIBLE        bool   `json:"-"`
 	style             int
 }
 
-type borderStyle struct {
-	inner     borderInner
-	outer     borderOuter
+// BorderStyle defines borders of the text table.
+type BorderStyle struct {
+	inner     BorderInner
+	outer     BorderOuter
 	widthFull bool
+	revision  uint64 // Incremented on changes affecting table layout
+
+	header       int                     // Style of the line under the header
+	footer       int                     // Style of the line above the footer
+	rowSeparator int                     // Style of the lines between rows, -1 is the grid style
+	separators   map[int]int             // Styles of the vertical lines right of the columns, -1 is for all
+	columns      map[int]*BorderInner    // Grid glyphs of the columns with own separators
+	groups       map[int]int             // Styles of the lines under header group levels, -1 is for all
+	groupLines   map[[2]int]*BorderInner // HEADER glyphs of the lines by level and column, -1 is for all
+
+	glyphMode  int       // GLYPHS_AUTO, GLYPHS_UNICODE or GLYPHS_ASCII
+	ascii      bool      // Glyphs are mapped to ASCII ones
+	borderText TextStyle // Colors and attributes of borders and grid
+	headerText TextStyle // Colors and attributes of the header row
+	footerText TextStyle // Colors and attributes of the footer row
+	borderSGR  string
+	headerSGR  string
+	footerSGR  string
+
+	// Glyphs of BORDER_CUSTOM style, restored when borders become visible again
+	customOuter BorderOuter
+	customInner BorderInner
+	err         error
 }
 
 // Configuration of the table.
@@ -43,19 +81,25 @@ const (
 	BORDER_SINGLE_THICK
 	BORDER_DOUBLE
 	BORDER_NONE
+	BORDER_CUSTOM
+	BORDER_DASHED
+	BORDER_ASCII
 )
 
-func NewBorderStyle(outer int, inner int) *borderStyle {
-	style := new(borderStyle)
+func NewBorderStyle(outer int, inner int) *BorderStyle {
+	style := new(BorderStyle)
 
-	style.outer = *new(borderOuter)
+	style.outer = *new(BorderOuter)
 	style.outer.IS_VISIBLE = true
 	style.outer.style = outer
 
-	style.inner = *new(borderInner)
+	style.inner = *new(BorderInner)
 	style.inner.IS_VISIBLE = true
 	style.inner.HEADER_IS_VISIBLE = true
 	style.inner.style = inner
+	style.header = BORDER_NONE
+	style.footer = BORDER_NONE
+	style.rowSeparator = -1
 	style.widthFull = false
 
 	style.initBorderStyle()
@@ -63,306 +107,592 @@ func NewBorderStyle(outer int, inner int) *borderStyle {
 	return style
 }
 
+/*
+NewCustomBorderStyle creates border style from user-defined glyphs. Each glyph
+must take one terminal cell. Vertical lines must be set, while horizontal ones,
+i.e. outer border, lines between rows and lines under the header and above
+the footer, can be omitted
+together with their junctions. Otherwise the error is reported by Validate.
+*/
+func NewCustomBorderStyle(outer BorderOuter, inner BorderInner) *BorderStyle {
+	style := new(BorderStyle)
+	style.customOuter = outer
+	style.customInner = inner
+
+	style.outer.IS_VISIBLE = true
+	style.outer.style = BORDER_CUSTOM
+	style.inner.IS_VISIBLE = true
+	style.inner.HEADER_IS_VISIBLE = true
+	style.inner.style = BORDER_CUSTOM
+	style.header = BORDER_NONE
+	style.footer = BORDER_NONE
+	style.rowSeparator = -1
+
+	if err := validateGlyphs(outer, inner, glyphFieldName); err != nil {
+		style.setError(fmt.Errorf("NewCustomBorderStyle: %w", err))
+	}
+	style.initBorderStyle()
+
+	return style
+}
+
+// Name of the glyph field, e.g. BorderOuter.LEFT_TOP
+func glyphFieldName(part string, field string) string {
+	if part == "outer" {
+		return "BorderOuter." + field
+	}
+	return "BorderInner." + field
+}
+
+/*
+Check that glyphs of each line are either all set or all empty and each one takes
+exactly one terminal cell. Errors name the glyph with the name function,
+which gets "outer" or "inner" and name of the field.
+*/
+func validateGlyphs(outer BorderOuter, inner BorderInner, name func(part string, field string) string) error {
+	type glyph struct {
+		part  string
+		field string
+		value string
+	}
+
+	measure := newDisplayWidth()
+	check := func(glyphs []glyph, optional bool) error {
+		empty := 0
+		for _, glyph := range glyphs {
+			if glyph.value == "" {
+				empty++
+			}
+		}
+		if optional && empty == len(glyphs) {
+			return nil
+		}
+
+		for _, glyph := range glyphs {
+			if glyph.value == "" {
+				return fmt.Errorf("%s is not set: %w", name(glyph.part, glyph.field), ErrInvalidOption)
+			} else if width := measure.width(glyph.value); width != 1 {
+				return fmt.Errorf("%s %q takes %d cells instead of one: %w", name(glyph.part, glyph.field), glyph.value, width, ErrInvalidOption)
+			}
+		}
+		return nil
+	}
+
+	lines := []struct {
+		glyphs   []glyph
+		optional bool
+	}{
+		{[]glyph{
+			{"outer", "VERTICAL_LINE", outer.VERTICAL_LINE},
+			{"inner", "VERTICAL_LINE", inner.VERTICAL_LINE},
+		}, false},
+		{[]glyph{
+			{"outer", "HORISONTAL_LINE", outer.HORISONTAL_LINE},
+			{"outer", "LEFT_TOP", outer.LEFT_TOP},
+			{"outer", "LEFT_BOTTOM", outer.LEFT_BOTTOM},
+			{"outer", "RIGHT_TOP", outer.RIGHT_TOP},
+			{"outer", "RIGHT_BOTTOM", outer.RIGHT_BOTTOM},
+			{"inner", "CENTER_TOP", inner.CENTER_TOP},
+			{"inner", "CENTER_BOTTOM", inner.CENTER_BOTTOM},
+		}, true},
+		{[]glyph{
+			{"inner", "HORISONTAL_LINE", inner.HORISONTAL_LINE},
+			{"inner", "LEFT_MIDDLE", inner.LEFT_MIDDLE},
+			{"inner", "CENTER_MIDDLE", inner.CENTER_MIDDLE},
+			{"inner", "RIGHT_MIDDLE", inner.RIGHT_MIDDLE},
+		}, true},
+		{[]glyph{
+			{"inner", "HEADER", inner.HEADER},
+			{"inner", "HEADER_LEFT", inner.HEADER_LEFT},
+			{"inner", "HEADER_MIDDLE", inner.HEADER_MIDDLE},
+			{"inner", "HEADER_RIGHT", inner.HEADER_RIGHT},
+		}, true},
+		{[]glyph{
+			{"inner", "FOOTER", inner.FOOTER},
+			{"inner", "FOOTER_LEFT", inner.FOOTER_LEFT},
+			{"inner", "FOOTER_MIDDLE", inner.FOOTER_MIDDLE},
+			{"inner", "FOOTER_RIGHT", inner.FOOTER_RIGHT},
+		}, true},
+	}
+	for _, line := range lines {
+		if err := check(line.glyphs, line.optional); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error, if the style is defined incompletely.
+func (style *BorderStyle) Validate() error {
+	return style.err
+}
+
 // Set table to be full width aligned to the terminal size.
 // If this is set to False, then table will be calculated according to the
 // data cells, if they are narrower than the terminal size.
-func (style *borderStyle) SetTableWidthFull(full bool) *borderStyle {
+func (style *BorderStyle) SetTableWidthFull(full bool) *BorderStyle {
 	style.widthFull = full
+	style.revision++
 	return style
 }
 
-func (style *borderStyle) initBorderStyle() *borderStyle {
-	switch style.outer.style {
-	case BORDER_SINGLE_THIN:
-		style.outer.LEFT_TOP = "\u250c"
-		style.outer.LEFT_BOTTOM = "\u2514"
-		style.outer.RIGHT_TOP = "\u2510"
-		style.outer.RIGHT_BOTTOM = "\u2518"
-		style.outer.HORISONTAL_LINE = "\u2500"
-		style.outer.VERTICAL_LINE = "\u2502"
-		switch style.inner.style {
-		case BORDER_SINGLE_THICK:
-			style.inner.CENTER_TOP = "\u2530"
-			style.inner.CENTER_BOTTOM = "\u2538"
-			style.inner.CENTER_MIDDLE = "\u254b"
-			style.inner.LEFT_MIDDLE = "\u251d"
-			style.inner.RIGHT_MIDDLE = "\u2525"
-			style.inner.HORISONTAL_LINE = "\u2501"
-			style.inner.VERTICAL_LINE = "\u2503"
-		case BORDER_DOUBLE:
-			style.inner.CENTER_TOP = "\u2565"
-			style.inner.CENTER_BOTTOM = "\u2568"
-			style.inner.CENTER_MIDDLE = "\u256c"
-			style.inner.LEFT_MIDDLE = "\u255e"
-			style.inner.RIGHT_MIDDLE = "\u2561"
-			style.inner.HORISONTAL_LINE = "\u2550"
-			style.inner.VERTICAL_LINE = "\u2551"
-		default: // Thin border
-			style.inner.CENTER_TOP = "\u252c"
-			style.inner.CENTER_BOTTOM = "\u2534"
-			style.inner.CENTER_MIDDLE = "\u253c"
-			style.inner.LEFT_MIDDLE = "\u251c"
-			style.inner.RIGHT_MIDDLE = "\u2524"
-			style.inner.HORISONTAL_LINE = "\u2500"
-			style.inner.VERTICAL_LINE = "\u2502"
+/*
+Compute glyphs of the style. Built-in styles are drawn by the junction engine
+from weights of outer, inner, header, footer and column separator lines, custom ones
+are restored from their definition. Invisible parts are cleared afterwards.
+Widths of the glyphs may change, so the revision is incremented.
+*/
+func (style *BorderStyle) initBorderStyle() *BorderStyle {
+	style.revision++
+	if style.outer.style == BORDER_CUSTOM {
+		outer, inner := style.customOuter, style.customInner
+		outer.IS_VISIBLE, outer.style = style.outer.IS_VISIBLE, style.outer.style
+		inner.IS_VISIBLE, inner.HEADER_IS_VISIBLE, inner.style = style.inner.IS_VISIBLE, style.inner.HEADER_IS_VISIBLE, style.inner.style
+		style.outer, style.inner = outer, inner
+		style.columns, style.groupLines = nil, nil
+	} else {
+		outer, inner := lineWeight(style.outer.style), lineWeight(style.inner.style)
+		if outer == _lineNone || outer == _lineAscii {
+			// Just a plug ugly ascii style
+			outer, inner = _lineAscii, _lineAscii
+		} else if inner == _lineNone || (inner == _lineAscii && style.inner.style != BORDER_ASCII) {
+			inner = _lineThin
+		}
+
+		horizontal, vertical := inner, inner
+		header, footer := style.gridWeight(style.header, outer), style.gridWeight(style.footer, outer)
+		if style.rowSeparator > -1 {
+			horizontal = style.gridWeight(style.rowSeparator, outer)
+		}
+		if separator, ok := style.separators[-1]; ok {
+			vertical = style.gridWeight(separator, outer)
+		}
+
+		style.outer.LEFT_TOP = junction(_lineNone, outer, outer, _lineNone)
+		style.outer.RIGHT_TOP = junction(_lineNone, _lineNone, outer, outer)
+		style.outer.LEFT_BOTTOM = junction(outer, outer, _lineNone, _lineNone)
+		style.outer.RIGHT_BOTTOM = junction(outer, _lineNone, _lineNone, outer)
+		style.outer.HORISONTAL_LINE = junction(_lineNone, outer, _lineNone, outer)
+		style.outer.VERTICAL_LINE = junction(outer, _lineNone, outer, _lineNone)
+		style.initGridGlyphs(&style.inner, outer, horizontal, vertical, header, footer)
+
+		style.columns = make(map[int]*BorderInner)
+		for column, separator := range style.separators {
+			if column > -1 {
+				glyphs := style.inner
+				style.initGridGlyphs(&glyphs, outer, horizontal, style.gridWeight(separator, outer), header, footer)
+				style.columns[column] = &glyphs
+			}
 		}
-	case BORDER_SINGLE_THICK:
-		style.outer.LEFT_TOP = "\u250f"
-		style.outer.LEFT_BOTTOM = "\u2517"
-		style.outer.RIGHT_TOP = "\u2513"
-		style.outer.RIGHT_BOTTOM = "\u251b"
-		style.outer.HORISONTAL_LINE = "\u2501"
-		style.outer.VERTICAL_LINE = "\u2503"
-
-		switch style.inner.style {
-		case BORDER_SINGLE_THICK:
-			style.inner.CENTER_TOP = "\u2533"
-			style.inner.CENTER_BOTTOM = "\u253b"
-			style.inner.CENTER_MIDDLE = "\u254b"
-			style.inner.LEFT_MIDDLE = "\u2523"
-			style.inner.RIGHT_MIDDLE = "\u252b"
-			style.inner.HORISONTAL_LINE = "\u2501"
-			style.inner.VERTICAL_LINE = "\u2503"
-		default: // Thin border, no double here
-			style.inner.CENTER_TOP = "\u252f"
-			style.inner.CENTER_BOTTOM = "\u2537"
-			style.inner.CENTER_MIDDLE = "\u253c"
-			style.inner.LEFT_MIDDLE = "\u2520"
-			style.inner.RIGHT_MIDDLE = "\u2528"
-			style.inner.HORISONTAL_LINE = "\u2500"
-			style.inner.VERTICAL_LINE = "\u2502"
+
+		// Lines under header group levels are drawn as the line under the header
+		style.groupLines = make(map[[2]int]*BorderInner)
+		for level, separator := range style.groups {
+			glyphs := style.inner
+			style.initGridGlyphs(&glyphs, outer, horizontal, vertical, style.gridWeight(separator, outer), footer)
+			style.groupLines[[2]int{level, -1}] = &glyphs
+			for column, columnSeparator := range style.separators {
+				if column > -1 {
+					glyphs := style.inner
+					style.initGridGlyphs(&glyphs, outer, horizontal, style.gridWeight(columnSeparator, outer), style.gridWeight(separator, outer), footer)
+					style.groupLines[[2]int{level, column}] = &glyphs
+				}
+			}
 		}
-	case BORDER_DOUBLE:
-		style.outer.LEFT_TOP = "\u2554"
-		style.outer.LEFT_BOTTOM = "\u255a"
-		style.outer.RIGHT_TOP = "\u2557"
-		style.outer.RIGHT_BOTTOM = "\u255d"
-		style.outer.HORISONTAL_LINE = "\u2550"
-		style.outer.VERTICAL_LINE = "\u2551"
-
-		switch style.inner.style {
-		case BORDER_DOUBLE:
-			style.inner.CENTER_TOP = "\u2566"
-			style.inner.CENTER_BOTTOM = "\u2569"
-			style.inner.CENTER_MIDDLE = "\u256c"
-			style.inner.LEFT_MIDDLE = "\u2560"
-			style.inner.RIGHT_MIDDLE = "\u2563"
-			style.inner.HORISONTAL_LINE = "\u2550"
-			style.inner.VERTICAL_LINE = "\u2551"
-		default: // Thin border, no thick here
-			style.inner.CENTER_TOP = "\u2564"
-			style.inner.CENTER_BOTTOM = "\u2567"
-			style.inner.CENTER_MIDDLE = "\u253c"
-			style.inner.LEFT_MIDDLE = "\u255f"
-			style.inner.RIGHT_MIDDLE = "\u2562"
-			style.inner.HORISONTAL_LINE = "\u2500"
-			style.inner.VERTICAL_LINE = "\u2502"
+	}
+
+	style.ascii = style.glyphMode == GLYPHS_ASCII || (style.glyphMode == GLYPHS_AUTO && !unicodeSupported())
+	if style.ascii {
+		style.asciiGlyphs()
+	}
+
+	if !style.outer.IS_VISIBLE {
+		style.outer.LEFT_TOP, style.outer.LEFT_BOTTOM, style.outer.RIGHT_TOP, style.outer.RIGHT_BOTTOM,
+			style.outer.HORISONTAL_LINE, style.outer.VERTICAL_LINE = "", "", "", "", "", ""
+	}
+	for _, inner := range append([]*BorderInner{&style.inner}, style.separatorGlyphs()...) {
+		if !style.outer.IS_VISIBLE {
+			inner.LEFT_MIDDLE, inner.RIGHT_MIDDLE, inner.CENTER_TOP, inner.CENTER_BOTTOM = "", "", "", ""
+		}
+		if !style.inner.IS_VISIBLE {
+			inner.CENTER_MIDDLE, inner.CENTER_BOTTOM, inner.CENTER_TOP, inner.VERTICAL_LINE,
+				inner.HORISONTAL_LINE, inner.LEFT_MIDDLE, inner.RIGHT_MIDDLE = "", "", "", "", "", "", ""
 		}
-	default:
-		// Just a plug ugly ascii style
-		style.outer.LEFT_TOP = "+"
-		style.outer.LEFT_BOTTOM = "+"
-		style.outer.RIGHT_TOP = "+"
-		style.outer.RIGHT_BOTTOM = "+"
-		style.outer.HORISONTAL_LINE = "-"
-		style.outer.VERTICAL_LINE = "|"
-		style.inner.CENTER_TOP = "+"
-		style.inner.CENTER_BOTTOM = "+"
-		style.inner.CENTER_MIDDLE = "+"
-		style.inner.LEFT_MIDDLE = "+"
-		style.inner.RIGHT_MIDDLE = "+"
-		style.inner.HORISONTAL_LINE = "-"
-		style.inner.VERTICAL_LINE = "|"
 	}
 
 	return style
 }
 
-func (style *borderStyle) SetHeaderVisible(visibility bool) *borderStyle {
-	style.inner.HEADER_IS_VISIBLE = visibility
-	return style
+// Resolve GLYPHS_AUTO mode at render time, as the locale and terminal might
+// differ from the ones, the style was built with.
+func (style *BorderStyle) resolveGlyphs() {
+	if style.glyphMode == GLYPHS_AUTO && style.ascii == unicodeSupported() {
+		style.initBorderStyle()
+	}
 }
 
-// Set outer border visibility
-func (style *borderStyle) SetBorderVisible(visibility bool) *borderStyle {
-	style.outer.IS_VISIBLE = visibility
-	if !style.outer.IS_VISIBLE {
-		style.outer.LEFT_TOP, style.outer.LEFT_BOTTOM, style.outer.RIGHT_TOP, style.outer.RIGHT_BOTTOM,
-			style.outer.HORISONTAL_LINE, style.outer.VERTICAL_LINE = "", "", "", "", "", ""
-		style.inner.LEFT_MIDDLE, style.inner.RIGHT_MIDDLE, style.inner.CENTER_TOP,
-			style.inner.CENTER_BOTTOM = "", "", "", ""
+// Get weight of the grid line style. Lines of ASCII styles are ASCII as well, heavy ones are drawn with "=".
+func (style *BorderStyle) gridWeight(lineStyle int, outer int) int {
+	weight := lineWeight(lineStyle)
+	if outer == _lineAscii && weight != _lineNone {
+		if weight == _lineThick || weight == _lineDouble {
+			return _lineAsciiDouble
+		}
+		return _lineAscii
+	}
+	return weight
+}
+
+// Map all glyphs to the closest ASCII ones
+func (style *BorderStyle) asciiGlyphs() {
+	for _, glyph := range []*string{&style.outer.VERTICAL_LINE, &style.outer.HORISONTAL_LINE,
+		&style.outer.LEFT_TOP, &style.outer.LEFT_BOTTOM, &style.outer.RIGHT_TOP, &style.outer.RIGHT_BOTTOM} {
+		*glyph = asciiGlyph(*glyph)
+	}
+	for _, inner := range append([]*BorderInner{&style.inner}, style.separatorGlyphs()...) {
+		for _, glyph := range []*string{&inner.VERTICAL_LINE, &inner.HORISONTAL_LINE, &inner.LEFT_MIDDLE,
+			&inner.CENTER_TOP, &inner.CENTER_BOTTOM, &inner.CENTER_MIDDLE, &inner.RIGHT_MIDDLE,
+			&inner.HEADER_LEFT, &inner.HEADER_MIDDLE, &inner.HEADER_RIGHT, &inner.HEADER,
+			&inner.FOOTER_LEFT, &inner.FOOTER_MIDDLE, &inner.FOOTER_RIGHT, &inner.FOOTER} {
+			*glyph = asciiGlyph(*glyph)
+		}
+	}
+}
+
+// Compute grid glyphs from weights of outer border, horizontal and vertical grid lines,
+// line under the header and line above the footer
+func (style *BorderStyle) initGridGlyphs(inner *BorderInner, outer int, horizontal int, vertical int, header int, footer int) {
+	inner.HORISONTAL_LINE = junction(_lineNone, horizontal, _lineNone, horizontal)
+	inner.VERTICAL_LINE = junction(vertical, _lineNone, vertical, _lineNone)
+	if vertical == _lineNone {
+		inner.VERTICAL_LINE = " " // Columns are still apart
+	}
+	inner.LEFT_MIDDLE = junction(outer, horizontal, outer, _lineNone)
+	inner.RIGHT_MIDDLE = junction(outer, _lineNone, outer, horizontal)
+	inner.CENTER_TOP = junction(_lineNone, outer, vertical, outer)
+	inner.CENTER_BOTTOM = junction(vertical, outer, _lineNone, outer)
+	inner.CENTER_MIDDLE = junction(vertical, horizontal, vertical, horizontal)
+	if horizontal == _lineNone {
+		inner.LEFT_MIDDLE, inner.HORISONTAL_LINE, inner.CENTER_MIDDLE, inner.RIGHT_MIDDLE = "", "", "", ""
+	}
+
+	if header == _lineNone {
+		inner.HEADER, inner.HEADER_LEFT, inner.HEADER_MIDDLE, inner.HEADER_RIGHT = "", "", "", ""
 	} else {
-		style.initBorderStyle()
+		inner.HEADER = junction(_lineNone, header, _lineNone, header)
+		inner.HEADER_LEFT = junction(outer, header, outer, _lineNone)
+		inner.HEADER_MIDDLE = junction(vertical, header, vertical, header)
+		inner.HEADER_RIGHT = junction(outer, _lineNone, outer, header)
 	}
 
+	if footer == _lineNone {
+		inner.FOOTER, inner.FOOTER_LEFT, inner.FOOTER_MIDDLE, inner.FOOTER_RIGHT = "", "", "", ""
+	} else {
+		inner.FOOTER = junction(_lineNone, footer, _lineNone, footer)
+		inner.FOOTER_LEFT = junction(outer, footer, outer, _lineNone)
+		inner.FOOTER_MIDDLE = junction(vertical, footer, vertical, footer)
+		inner.FOOTER_RIGHT = junction(outer, _lineNone, outer, footer)
+	}
+}
+
+// Get computed glyphs of all column separators and lines under header group levels
+func (style *BorderStyle) separatorGlyphs() []*BorderInner {
+	glyphs := make([]*BorderInner, 0, len(style.columns)+len(style.groupLines))
+	for _, inner := range style.columns {
+		glyphs = append(glyphs, inner)
+	}
+	for _, inner := range style.groupLines {
+		glyphs = append(glyphs, inner)
+	}
+	return glyphs
+}
+
+// Get glyphs of the line under the header group level, used right of the column.
+// Nil means the level has no own line.
+func (style *BorderStyle) groupSeparator(level int, column int) *BorderInner {
+	for _, key := range [][2]int{{level, column}, {level, -1}, {-1, column}, {-1, -1}} {
+		if inner, ok := style.groupLines[key]; ok {
+			return inner
+		}
+	}
+	return nil
+}
+
+// Get grid glyphs, used right of the column
+func (style *BorderStyle) separator(column int) *BorderInner {
+	if inner, ok := style.columns[column]; ok {
+		return inner
+	}
+	return &style.inner
+}
+
+func (style *BorderStyle) SetHeaderVisible(visibility bool) *BorderStyle {
+	style.inner.HEADER_IS_VISIBLE = visibility
 	return style
 }
 
+// Set outer border visibility
+func (style *BorderStyle) SetBorderVisible(visibility bool) *BorderStyle {
+	style.outer.IS_VISIBLE = visibility
+	return style.initBorderStyle()
+}
+
 // Set table grid visibility
-func (style *borderStyle) SetGridVisible(visibility bool) *borderStyle {
+func (style *BorderStyle) SetGridVisible(visibility bool) *BorderStyle {
 	style.inner.IS_VISIBLE = visibility
-	if !style.inner.IS_VISIBLE {
-		style.inner.CENTER_MIDDLE = ""
-		style.inner.CENTER_BOTTOM = ""
-		style.inner.CENTER_TOP = ""
-		style.inner.VERTICAL_LINE = ""
-		style.inner.HORISONTAL_LINE = ""
-		style.inner.LEFT_MIDDLE = ""
-		style.inner.RIGHT_MIDDLE = ""
-	} else {
-		style.initBorderStyle()
-		style.SetBorderVisible(style.outer.IS_VISIBLE)
+	return style.initBorderStyle()
+}
+
+/*
+Set header style. This will draw a specified style line under the header,
+BORDER_NONE removes it. Custom styles define header glyphs themselves.
+*/
+func (style *BorderStyle) SetHeaderStyle(header int) *BorderStyle {
+	if !isLineStyle(header) {
+		style.setError(fmt.Errorf("SetHeaderStyle: unknown style %d: %w", header, ErrInvalidOption))
+		return style
 	}
+	style.header = header
+	return style.initBorderStyle()
+}
 
-	return style
+/*
+Set footer style. This will draw a specified style line above the footer,
+BORDER_NONE draws the line between rows instead. Custom styles define
+footer glyphs themselves.
+*/
+func (style *BorderStyle) SetFooterStyle(footer int) *BorderStyle {
+	if !isLineStyle(footer) {
+		style.setError(fmt.Errorf("SetFooterStyle: unknown style %d: %w", footer, ErrInvalidOption))
+		return style
+	}
+	style.footer = footer
+	return style.initBorderStyle()
 }
 
-// Set header style. This will draw a specified style line under the header.
-func (style *borderStyle) SetHeaderStyle(header int) *borderStyle {
-	switch header {
-	case BORDER_SINGLE_THIN:
-		if style.inner.IS_VISIBLE {
-			switch style.inner.style {
-			case BORDER_SINGLE_THIN:
-				style.inner.HEADER = "\u2500"
-				style.inner.HEADER_MIDDLE = "\u253c"
-				switch style.outer.style {
-				case BORDER_SINGLE_THIN:
-					style.inner.HEADER_LEFT = "\u251c"
-					style.inner.HEADER_RIGHT = "\u2524"
-				case BORDER_SINGLE_THICK:
-					style.inner.HEADER_LEFT = "\u2520"
-					style.inner.HEADER_RIGHT = "\u2528"
-				case BORDER_DOUBLE:
-					style.inner.HEADER_LEFT = "\u255f"
-					style.inner.HEADER_RIGHT = "\u2562"
-				}
+/*
+Set style of the vertical line right of the columns: BORDER_SINGLE_THIN,
+BORDER_SINGLE_THICK, BORDER_DOUBLE, BORDER_DASHED, BORDER_ASCII or BORDER_NONE,
+which keeps columns apart with a space. If columns contains only one value
+and it is -1, then style applies to all columns at once. Custom styles ignore it.
+*/
+func (style *BorderStyle) SetColSeparator(separator int, columns ...int) *BorderStyle {
+	if !isLineStyle(separator) {
+		style.setError(fmt.Errorf("SetColSeparator: unknown style %d: %w", separator, ErrInvalidOption))
+		return style
+	}
+
+	if style.separators == nil {
+		style.separators = make(map[int]int)
+	}
+	if len(columns) == 1 && columns[0] == -1 {
+		style.separators = map[int]int{-1: separator}
+	} else {
+		for _, column := range columns {
+			if column < 0 {
+				style.setError(fmt.Errorf("SetColSeparator: column %d: %w", column, ErrColumnOutOfRange))
+				return style
 			}
-		} else {
-			style.inner.HEADER = "\u2500"
-			style.inner.HEADER_LEFT = "\u2500"
-			style.inner.HEADER_MIDDLE = "\u2500"
-			style.inner.HEADER_RIGHT = "\u2500"
 		}
-	case BORDER_SINGLE_THICK:
-		if style.inner.IS_VISIBLE {
-			switch style.inner.style {
-			case BORDER_SINGLE_THIN:
-				style.inner.HEADER = "\u2501"
-				style.inner.HEADER_MIDDLE = "\u253f"
-				switch style.outer.style {
-				case BORDER_SINGLE_THIN:
-					style.inner.HEADER_LEFT = "\u251d"
-					style.inner.HEADER_RIGHT = "\u2525"
-				case BORDER_SINGLE_THICK:
-					style.inner.HEADER_LEFT = "\u2523"
-					style.inner.HEADER_RIGHT = "\u252b"
-				case BORDER_DOUBLE:
-					style.inner.HEADER_LEFT = "\u2560"
-					style.inner.HEADER_RIGHT = "\u2563"
-				}
-			}
-		} else {
-			style.inner.HEADER = "\u2501"
-			style.inner.HEADER_LEFT = "\u2501"
-			style.inner.HEADER_MIDDLE = "\u2501"
-			style.inner.HEADER_RIGHT = "\u2501"
+		for _, column := range columns {
+			style.separators[column] = separator
 		}
-	case BORDER_DOUBLE:
-		if style.inner.IS_VISIBLE {
-			switch style.inner.style {
-			case BORDER_SINGLE_THIN:
-				style.inner.HEADER = "\u2550"
-				style.inner.HEADER_MIDDLE = "\u256a"
-				switch style.outer.style {
-				case BORDER_SINGLE_THIN:
-					style.inner.HEADER_LEFT = "\u255e"
-					style.inner.HEADER_RIGHT = "\u2561"
-				case BORDER_SINGLE_THICK:
-					style.inner.HEADER_LEFT = "\u2503"
-					style.inner.HEADER_MIDDLE = "\u2584"
-					style.inner.HEADER_RIGHT = "\u2503"
-					style.inner.HEADER = "\u2584"
-				case BORDER_DOUBLE:
-					style.inner.HEADER_LEFT = "\u2560"
-					style.inner.HEADER_RIGHT = "\u2563"
-				}
+	}
+
+	return style.initBorderStyle()
+}
+
+/*
+Set style of the lines under header group levels, which are drawn as the line
+under the header. By default they are drawn as lines between rows. If levels
+contains only one value and it is -1, then style applies to all levels at once.
+Custom styles ignore it.
+*/
+func (style *BorderStyle) SetHeaderGroupStyle(separator int, levels ...int) *BorderStyle {
+	if !isLineStyle(separator) {
+		style.setError(fmt.Errorf("SetHeaderGroupStyle: unknown style %d: %w", separator, ErrInvalidOption))
+		return style
+	}
+
+	if style.groups == nil {
+		style.groups = make(map[int]int)
+	}
+	if len(levels) == 1 && levels[0] == -1 {
+		style.groups = map[int]int{-1: separator}
+	} else {
+		for _, level := range levels {
+			if level < 0 {
+				style.setError(fmt.Errorf("SetHeaderGroupStyle: level %d: %w", level, ErrRowOutOfRange))
+				return style
 			}
-		} else {
-			style.inner.HEADER = "\u2550"
-			style.inner.HEADER_LEFT = "\u2550"
-			style.inner.HEADER_MIDDLE = "\u2550"
-			style.inner.HEADER_RIGHT = "\u2550"
+		}
+		for _, level := range levels {
+			style.groups[level] = separator
 		}
 	}
+
+	return style.initBorderStyle()
+}
+
+/*
+Set style of the horizontal lines between rows. BORDER_NONE removes them,
+so rows follow each other. Custom styles ignore it.
+*/
+func (style *BorderStyle) SetRowSeparator(separator int) *BorderStyle {
+	if !isLineStyle(separator) {
+		style.setError(fmt.Errorf("SetRowSeparator: unknown style %d: %w", separator, ErrInvalidOption))
+		return style
+	}
+	style.rowSeparator = separator
+	return style.initBorderStyle()
+}
+
+// Check if the style is a line style
+func isLineStyle(lineStyle int) bool {
+	switch lineStyle {
+	case BORDER_SINGLE_THIN, BORDER_SINGLE_THICK, BORDER_DOUBLE, BORDER_DASHED, BORDER_ASCII, BORDER_NONE:
+		return true
+	}
+	return false
+}
+
+/*
+Set glyph mode: GLYPHS_AUTO (default) draws Unicode only if the locale and
+terminal can display it, which is checked on each render. GLYPHS_UNICODE and
+GLYPHS_ASCII force either one.
+In ASCII mode any style, including custom ones, is mapped to the closest ASCII glyphs.
+*/
+func (style *BorderStyle) SetGlyphMode(mode int) *BorderStyle {
+	if mode != GLYPHS_AUTO && mode != GLYPHS_UNICODE && mode != GLYPHS_ASCII {
+		style.setError(fmt.Errorf("SetGlyphMode: unknown mode %d: %w", mode, ErrInvalidOption))
+		return style
+	}
+	style.glyphMode = mode
+	return style.initBorderStyle()
+}
+
+// Set colors and attributes of borders and grid
+func (style *BorderStyle) SetBorderTextStyle(textStyle TextStyle) *BorderStyle {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		style.setError(fmt.Errorf("SetBorderTextStyle: %w", err))
+		return style
+	}
+	style.borderText, style.borderSGR = textStyle, sgr
 	return style
 }
 
+// Set colors and attributes of the header row
+func (style *BorderStyle) SetHeaderTextStyle(textStyle TextStyle) *BorderStyle {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		style.setError(fmt.Errorf("SetHeaderTextStyle: %w", err))
+		return style
+	}
+	style.headerText, style.headerSGR = textStyle, sgr
+	return style
+}
+
+// Set colors and attributes of the footer row
+func (style *BorderStyle) SetFooterTextStyle(textStyle TextStyle) *BorderStyle {
+	sgr, err := textStyle.sgr()
+	if err != nil {
+		style.setError(fmt.Errorf("SetFooterTextStyle: %w", err))
+		return style
+	}
+	style.footerText, style.footerSGR = textStyle, sgr
+	return style
+}
+
+// Record the error, unless there is one already
+func (style *BorderStyle) setError(err error) {
+	if style.err == nil {
+		style.err = err
+	}
+}
+
 // Outer
-func (border *borderOuter) LeftTop() string {
+func (border *BorderOuter) LeftTop() string {
 	return border.LEFT_TOP
 }
 
-func (border *borderOuter) RightTop() string {
+func (border *BorderOuter) RightTop() string {
 	return border.RIGHT_TOP
 }
 
-func (border *borderOuter) LeftBottom() string {
+func (border *BorderOuter) LeftBottom() string {
 	return border.LEFT_BOTTOM
 }
 
-func (border *borderOuter) RightBottom() string {
+func (border *BorderOuter) RightBottom() string {
 	return border.RIGHT_BOTTOM
 }
 
-func (border *borderOuter) HorisontalLine() string {
+func (border *BorderOuter) HorisontalLine() string {
 	return border.HORISONTAL_LINE
 }
 
-func (border *borderOuter) VerticalLine() string {
+func (border *BorderOuter) VerticalLine() string {
 	return border.VERTICAL_LINE
 }
 
 // Inner
-func (border *borderInner) LeftMiddle() string {
+func (border *BorderInner) LeftMiddle() string {
 	return border.LEFT_MIDDLE
 }
 
-func (border *borderInner) CenterMiddle() string {
+func (border *BorderInner) CenterMiddle() string {
 	return border.CENTER_MIDDLE
 }
-func (border *borderInner) RightMiddle() string {
+func (border *BorderInner) RightMiddle() string {
 	return border.RIGHT_MIDDLE
 
 }
-func (border *borderInner) CenterTop() string {
+func (border *BorderInner) CenterTop() string {
 	return border.CENTER_TOP
 
 }
-func (border *borderInner) CenterBottom() string {
+func (border *BorderInner) CenterBottom() string {
 	return border.CENTER_BOTTOM
 
 }
-func (border *borderInner) HorisontalLine() string {
+func (border *BorderInner) HorisontalLine() string {
 	return border.HORISONTAL_LINE
 
 }
-func (border *borderInner) VerticalLine() string {
+func (border *BorderInner) VerticalLine() string {
 	return border.VERTICAL_LINE
 }
 
-func (border *borderInner) Header() string {
+func (border *BorderInner) Header() string {
 	return border.HEADER
 }
 
-func (border *borderInner) HeaderLeft() string {
+func (border *BorderInner) HeaderLeft() string {
 	return border.HEADER_LEFT
 }
 
-func (border *borderInner) HeaderMiddle() string {
+func (border *BorderInner) HeaderMiddle() string {
 	return border.HEADER_MIDDLE
 }
 
-func (border *borderInner) HeaderRight() string {
+func (border *BorderInner) HeaderRight() string {
 	return border.HEADER_RIGHT
 }
+
+func (border *BorderInner) Footer() string {
+	return border.FOOTER
+}
+
+func (border *BorderInner) FooterLeft() string {
+	return border.FOOTER_LEFT
+}
+
+func (border *BorderInner) FooterMiddle() string {
+	return border.FOOTER_MIDDLE
+}
+
+func (border *BorderInner) FooterRight() string {
+	return border.FOOTER_RIGHT
+}
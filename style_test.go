@@ -0,0 +1,93 @@
+package asciitable
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func customGlyphs() (BorderOuter, BorderInner) {
+	outer := BorderOuter{VERTICAL_LINE: "|", HORISONTAL_LINE: "=", LEFT_TOP: "/", LEFT_BOTTOM: "\\",
+		RIGHT_TOP: "\\", RIGHT_BOTTOM: "/"}
+	inner := BorderInner{VERTICAL_LINE: ":", HORISONTAL_LINE: "-", LEFT_MIDDLE: ">", CENTER_TOP: "v",
+		CENTER_BOTTOM: "^", CENTER_MIDDLE: "+", RIGHT_MIDDLE: "<"}
+	return outer, inner
+}
+
+func TestCustomBorderStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		glyphs   func(outer *BorderOuter, inner *BorderInner)
+		expected string
+	}{
+		{"all glyphs", func(outer *BorderOuter, inner *BorderInner) {},
+			"\n/=====v==\\\n|Host :ID|\n|alpha:1 |\n\\=====^==/"},
+		{"vertical lines only", func(outer *BorderOuter, inner *BorderInner) {
+			*outer = BorderOuter{VERTICAL_LINE: "|"}
+			*inner = BorderInner{VERTICAL_LINE: ":"}
+		}, "\n|Host :ID|\n|alpha:1 |"},
+		{"box drawing glyphs", func(outer *BorderOuter, inner *BorderInner) {
+			outer.HORISONTAL_LINE = "─"
+			inner.VERTICAL_LINE = "│"
+		}, "\n/─────v──\\\n|Host │ID|\n|alpha│1 |\n\\─────^──/"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outer, inner := customGlyphs()
+			test.glyphs(&outer, &inner)
+			style := NewCustomBorderStyle(outer, inner).SetGlyphMode(GLYPHS_UNICODE)
+			if err := style.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			rendered := NewSimpleTable(NewTableData().SetHeader("Host", "ID").AddRow("alpha", 1), style).Render()
+			if rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+		})
+	}
+}
+
+func TestCustomBorderStyleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		glyphs  func(outer *BorderOuter, inner *BorderInner)
+		message string
+	}{
+		{"no vertical line", func(outer *BorderOuter, inner *BorderInner) { outer.VERTICAL_LINE = "" },
+			"BorderOuter.VERTICAL_LINE is not set"},
+		{"missing junction", func(outer *BorderOuter, inner *BorderInner) { inner.CENTER_BOTTOM = "" },
+			"BorderInner.CENTER_BOTTOM is not set"},
+		{"missing line", func(outer *BorderOuter, inner *BorderInner) { inner.HORISONTAL_LINE = "" },
+			"BorderInner.HORISONTAL_LINE is not set"},
+		{"partial header", func(outer *BorderOuter, inner *BorderInner) { inner.HEADER = "~" },
+			"BorderInner.HEADER_LEFT is not set"},
+		{"two glyphs", func(outer *BorderOuter, inner *BorderInner) { outer.HORISONTAL_LINE = "==" },
+			`BorderOuter.HORISONTAL_LINE "==" takes 2 cells instead of one`},
+		{"wide glyph", func(outer *BorderOuter, inner *BorderInner) { inner.VERTICAL_LINE = "｜" },
+			`BorderInner.VERTICAL_LINE "｜" takes 2 cells instead of one`},
+		{"zero width glyph", func(outer *BorderOuter, inner *BorderInner) { outer.LEFT_TOP = "́" },
+			`BorderOuter.LEFT_TOP "́" takes 0 cells instead of one`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outer, inner := customGlyphs()
+			test.glyphs(&outer, &inner)
+			style := NewCustomBorderStyle(outer, inner)
+			err := style.Validate()
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("Validate() = %v, expected %v", err, ErrInvalidOption)
+			}
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("Validate() = %q, expected to contain %q", err, test.message)
+			}
+
+			table := NewSimpleTable(NewTableData().SetHeader("Host").AddRow("alpha"), style)
+			if err := table.RenderTo(&strings.Builder{}); !errors.Is(err, ErrInvalidOption) {
+				t.Errorf("RenderTo() = %v, expected %v", err, ErrInvalidOption)
+			}
+			if rendered := table.Render(); rendered != "" {
+				t.Errorf("Render() = %q, expected nothing", rendered)
+			}
+		})
+	}
+}
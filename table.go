@@ -0,0 +1,38 @@
+package asciitable
+
+import (
+	"io"
+)
+
+/*
+Table is implemented by all table renderers. Fluent setters, like SetWidth
+or SetColAlign, return the concrete type, so they are not the part of it.
+Tables, which have width, implement Sizer as well.
+*/
+type Table interface {
+	// Data returns the data of the table
+	Data() *TableData
+	// Validate returns the first configuration error, if any
+	Validate() error
+	// Render returns the table as a string, or empty string, if the table is not valid
+	Render() string
+	// RenderTo writes the table to the writer
+	RenderTo(writer io.Writer) error
+}
+
+/*
+Sizer is implemented by tables, which are rendered to the width, e.g. of
+the terminal. SetTableWidth is SetWidth of the table, returning the interface.
+*/
+type Sizer interface {
+	Table
+	// SetTableWidth sets overall table width in terminal cells
+	SetTableWidth(width int) Sizer
+}
+
+var (
+	_ Sizer = (*SimpleTable)(nil)
+	_ Table = (*SimpleTable)(nil)
+	_ Table = (*MarkdownTable)(nil)
+	_ Table = (*HTMLTable)(nil)
+)
@@ -0,0 +1,19 @@
+package asciitable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSizer(t *testing.T) {
+	data := NewTableData().SetHeader("Name", "Description").AddRow("Strawberry", "Fruit in yours granma's garten")
+	var table Sizer = NewSimpleTable(data, NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN)).SetColorMode(COLOR_NEVER)
+
+	for _, width := range []int{20, 30} {
+		for _, line := range strings.Split(strings.TrimPrefix(table.SetTableWidth(width).Render(), "\n"), "\n") {
+			if lineWidth := table.(*SimpleTable).textWidth(line); lineWidth != width {
+				t.Errorf("SetTableWidth(%d): line %q is %d wide", width, line, lineWidth)
+			}
+		}
+	}
+}
@@ -1,26 +1,101 @@
 package asciitable
 
 import (
-	"syscall"
-	"unsafe"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 )
 
-type termSize struct {
-	Row    uint16
-	Col    uint16
-	Xpixel uint16
-	Ypixel uint16
+// TerminalSizeProvider returns columns and rows of the terminal.
+type TerminalSizeProvider func() (int, int, error)
+
+// Terminal size detection settings
+var (
+	_terminalLock        sync.Mutex
+	_terminalProvider    TerminalSizeProvider
+	_terminalDefaultCols = 80
+	_terminalDefaultRows = 24
+)
+
+/*
+SetTerminalSizeProvider replaces terminal size detection with a custom provider,
+e.g. for tests or when output goes to something else than a terminal.
+Setting nil restores the detection.
+*/
+func SetTerminalSizeProvider(provider TerminalSizeProvider) {
+	_terminalLock.Lock()
+	defer _terminalLock.Unlock()
+	_terminalProvider = provider
 }
 
-func GetTerminalSize() (int, int) {
-	size := &termSize{}
-	code, _, err := syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(syscall.Stdin),
-		uintptr(syscall.TIOCGWINSZ),
-		uintptr(unsafe.Pointer(size)))
+/*
+SetDefaultTerminalSize sets size, returned when terminal size can not be
+detected. Default is 80x24. Values less than one are ignored.
+*/
+func SetDefaultTerminalSize(cols int, rows int) {
+	_terminalLock.Lock()
+	defer _terminalLock.Unlock()
+	if cols > 0 {
+		_terminalDefaultCols = cols
+	}
+	if rows > 0 {
+		_terminalDefaultRows = rows
+	}
+}
 
-	if int(code) == -1 {
-		panic(err)
+// Get positive size from the environment variable, or zero
+func envTerminalSize(name string) int {
+	size, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || size < 0 {
+		return 0
 	}
-	return int(size.Col), int(size.Row)
+	return size
+}
+
+/*
+GetTerminalSize returns columns and rows of the terminal. If a provider is set,
+only its result is used. Otherwise COLUMNS and LINES environment variables take
+precedence, then the size is queried from stdout, stderr, stdin and /dev/tty.
+If the size can not be obtained, default size is returned together with an
+error wrapping ErrTerminalSize.
+*/
+func GetTerminalSize() (int, int, error) {
+	return terminalSize(true)
+}
+
+// Get terminal size, either with environment variables taking precedence, or
+// queried from the terminal only. Environment is fixed at startup, so
+// the size after a resize is only the queried one.
+func terminalSize(env bool) (int, int, error) {
+	_terminalLock.Lock()
+	provider, defaultCols, defaultRows := _terminalProvider, _terminalDefaultCols, _terminalDefaultRows
+	_terminalLock.Unlock()
+
+	var cols, rows int
+	var err error
+	if provider != nil {
+		cols, rows, err = provider()
+	} else {
+		cols, rows, err = queryTerminalSize()
+		if envCols := envTerminalSize("COLUMNS"); env && envCols > 0 {
+			cols, err = envCols, nil
+		}
+		if envRows := envTerminalSize("LINES"); env && envRows > 0 {
+			rows = envRows
+		}
+	}
+
+	if err == nil && cols < 1 {
+		err = fmt.Errorf("zero terminal width")
+	}
+	if err != nil {
+		return defaultCols, defaultRows, fmt.Errorf("%w: %v", ErrTerminalSize, err)
+	}
+	if rows < 1 {
+		rows = defaultRows
+	}
+
+	return cols, rows, nil
 }
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package asciitable
+
+import (
+	"errors"
+)
+
+// Terminal size query is not supported on this platform,
+// only providers, environment and defaults are used.
+func queryTerminalSize() (int, int, error) {
+	return 0, 0, errors.New("terminal size query is not supported on this platform")
+}
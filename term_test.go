@@ -0,0 +1,28 @@
+package asciitable
+
+import "testing"
+
+func TestTerminalSizeEnvironment(t *testing.T) {
+	t.Setenv("COLUMNS", "33")
+	t.Setenv("LINES", "11")
+
+	if cols, rows, err := GetTerminalSize(); err != nil || cols != 33 || rows != 11 {
+		t.Errorf("GetTerminalSize() = %d, %d, %v, expected 33, 11", cols, rows, err)
+	}
+	// Resize watcher queries the terminal, since environment keeps the size at startup
+	if cols, rows, _ := terminalSize(false); cols == 33 || rows == 11 {
+		t.Errorf("terminalSize(false) = %d, %d, expected size not from the environment", cols, rows)
+	}
+}
+
+func TestTerminalSizeProvider(t *testing.T) {
+	t.Setenv("COLUMNS", "33")
+	SetTerminalSizeProvider(func() (int, int, error) { return 120, 40, nil })
+	defer SetTerminalSizeProvider(nil)
+
+	for _, env := range []bool{true, false} {
+		if cols, rows, err := terminalSize(env); err != nil || cols != 120 || rows != 40 {
+			t.Errorf("terminalSize(%v) = %d, %d, %v, expected 120, 40", env, cols, rows, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package asciitable
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type termSize struct {
+	Row    uint16
+	Col    uint16
+	Xpixel uint16
+	Ypixel uint16
+}
+
+// Query terminal size of the file descriptor
+func ioctlTerminalSize(fd uintptr) (int, int, error) {
+	size := &termSize{}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd,
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(size)))
+
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(size.Col), int(size.Row), nil
+}
+
+// Query terminal size from any of the standard streams, which is still
+// a terminal, or from the controlling terminal, when all are redirected.
+func queryTerminalSize() (int, int, error) {
+	var err error
+	for _, fd := range []uintptr{uintptr(syscall.Stdout), uintptr(syscall.Stderr), uintptr(syscall.Stdin)} {
+		var cols, rows int
+		if cols, rows, err = ioctlTerminalSize(fd); err == nil && cols > 0 {
+			return cols, rows, nil
+		}
+	}
+
+	tty, ttyErr := os.Open("/dev/tty")
+	if ttyErr != nil {
+		return 0, 0, err
+	}
+	defer tty.Close()
+
+	return ioctlTerminalSize(tty.Fd())
+}
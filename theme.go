@@ -0,0 +1,327 @@
+package asciitable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Line style names, used in themes
+var _lineStyleNames = map[string]int{
+	"thin":   BORDER_SINGLE_THIN,
+	"thick":  BORDER_SINGLE_THICK,
+	"double": BORDER_DOUBLE,
+	"dashed": BORDER_DASHED,
+	"ascii":  BORDER_ASCII,
+	"none":   BORDER_NONE,
+}
+
+/*
+Theme is a serializable definition of a border style. Line styles are named:
+thin, thick, double, dashed, ascii and none. Outer style can also be custom,
+which takes all glyphs from Glyphs. Columns map column index (-1 for all)
+to its separator style, HeaderGroups map header group level (-1 for all)
+to the style of the line under it. Charset is auto, unicode or ascii.
+*/
+type Theme struct {
+	Outer         string         `json:"outer,omitempty"`
+	Inner         string         `json:"inner,omitempty"`
+	Header        string         `json:"header,omitempty"`
+	Footer        string         `json:"footer,omitempty"`
+	Rows          string         `json:"rows,omitempty"`
+	Columns       map[int]string `json:"columns,omitempty"`
+	HeaderGroups  map[int]string `json:"header_groups,omitempty"`
+	BorderVisible *bool          `json:"border_visible,omitempty"`
+	GridVisible   *bool          `json:"grid_visible,omitempty"`
+	HeaderVisible *bool          `json:"header_visible,omitempty"`
+	WidthFull     bool           `json:"width_full,omitempty"`
+	Glyphs        *ThemeGlyphs   `json:"glyphs,omitempty"`
+	Charset       string         `json:"charset,omitempty"`
+	BorderText    *TextStyle     `json:"border_text,omitempty"`
+	HeaderText    *TextStyle     `json:"header_text,omitempty"`
+	FooterText    *TextStyle     `json:"footer_text,omitempty"`
+}
+
+// ThemeGlyphs are glyphs of the custom theme
+type ThemeGlyphs struct {
+	Outer BorderOuter `json:"outer"`
+	Inner BorderInner `json:"inner"`
+}
+
+// Get line style by name. Empty name is allowed only if fallback is given.
+func themeLineStyle(field string, name string, fallback *int) (int, error) {
+	if name == "" && fallback != nil {
+		return *fallback, nil
+	}
+	if lineStyle, ok := _lineStyleNames[name]; ok {
+		return lineStyle, nil
+	}
+
+	names := make([]string, 0, len(_lineStyleNames))
+	for name := range _lineStyleNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return 0, fmt.Errorf("theme: %s: unknown style %q, expected one of %s: %w", field, name, strings.Join(names, ", "), ErrInvalidOption)
+}
+
+// Get name of the line style. Unknown styles are drawn as the fallback one.
+func themeLineName(lineStyle int, fallback string) string {
+	for name, style := range _lineStyleNames {
+		if style == lineStyle {
+			return name
+		}
+	}
+	return fallback
+}
+
+/*
+NewBorderStyleFromTheme creates border style from the theme. Errors name
+the offending field of the theme, e.g. "theme: glyphs.outer.left_top is not set".
+*/
+func NewBorderStyleFromTheme(theme Theme) (*BorderStyle, error) {
+	var style *BorderStyle
+	if theme.Outer == "custom" || (theme.Outer == "" && theme.Glyphs != nil) {
+		if theme.Glyphs == nil {
+			return nil, fmt.Errorf("theme: glyphs: required by custom style: %w", ErrInvalidOption)
+		}
+		for field, value := range map[string]string{"inner": theme.Inner, "header": theme.Header, "footer": theme.Footer, "rows": theme.Rows} {
+			if value != "" {
+				return nil, fmt.Errorf("theme: %s: custom style defines it with glyphs: %w", field, ErrInvalidOption)
+			}
+		}
+		if len(theme.Columns) > 0 {
+			return nil, fmt.Errorf("theme: columns: custom style defines them with glyphs: %w", ErrInvalidOption)
+		}
+		if len(theme.HeaderGroups) > 0 {
+			return nil, fmt.Errorf("theme: header_groups: custom style draws them with inner glyphs: %w", ErrInvalidOption)
+		}
+
+		err := validateGlyphs(theme.Glyphs.Outer, theme.Glyphs.Inner, func(part string, field string) string {
+			return "glyphs." + part + "." + strings.ToLower(field)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("theme: %w", err)
+		}
+		style = NewCustomBorderStyle(theme.Glyphs.Outer, theme.Glyphs.Inner)
+	} else {
+		if theme.Glyphs != nil {
+			return nil, fmt.Errorf("theme: glyphs: allowed only with custom outer style: %w", ErrInvalidOption)
+		}
+
+		outer, err := themeLineStyle("outer", theme.Outer, nil)
+		if err != nil {
+			return nil, err
+		}
+		inner, err := themeLineStyle("inner", theme.Inner, &outer)
+		if err != nil {
+			return nil, err
+		}
+		none := BORDER_NONE
+		header, err := themeLineStyle("header", theme.Header, &none)
+		if err != nil {
+			return nil, err
+		}
+		footer, err := themeLineStyle("footer", theme.Footer, &none)
+		if err != nil {
+			return nil, err
+		}
+		style = NewBorderStyle(outer, inner).SetHeaderStyle(header).SetFooterStyle(footer)
+
+		if theme.Rows != "" {
+			rows, err := themeLineStyle("rows", theme.Rows, nil)
+			if err != nil {
+				return nil, err
+			}
+			style.SetRowSeparator(rows)
+		}
+
+		columns := make([]int, 0, len(theme.Columns))
+		for column := range theme.Columns {
+			columns = append(columns, column)
+		}
+		sort.Ints(columns) // Separator for all columns goes first
+		for _, column := range columns {
+			field := fmt.Sprintf("columns.%d", column)
+			if column < -1 {
+				return nil, fmt.Errorf("theme: %s: %w", field, ErrColumnOutOfRange)
+			}
+			separator, err := themeLineStyle(field, theme.Columns[column], nil)
+			if err != nil {
+				return nil, err
+			}
+			style.SetColSeparator(separator, column)
+		}
+
+		levels := make([]int, 0, len(theme.HeaderGroups))
+		for level := range theme.HeaderGroups {
+			levels = append(levels, level)
+		}
+		sort.Ints(levels) // Style for all levels goes first
+		for _, level := range levels {
+			field := fmt.Sprintf("header_groups.%d", level)
+			if level < -1 {
+				return nil, fmt.Errorf("theme: %s: %w", field, ErrRowOutOfRange)
+			}
+			separator, err := themeLineStyle(field, theme.HeaderGroups[level], nil)
+			if err != nil {
+				return nil, err
+			}
+			style.SetHeaderGroupStyle(separator, level)
+		}
+	}
+
+	if theme.BorderVisible != nil {
+		style.SetBorderVisible(*theme.BorderVisible)
+	}
+	if theme.GridVisible != nil {
+		style.SetGridVisible(*theme.GridVisible)
+	}
+	if theme.HeaderVisible != nil {
+		style.SetHeaderVisible(*theme.HeaderVisible)
+	}
+	style.SetTableWidthFull(theme.WidthFull)
+
+	switch theme.Charset {
+	case "", "auto":
+	case "unicode":
+		style.SetGlyphMode(GLYPHS_UNICODE)
+	case "ascii":
+		style.SetGlyphMode(GLYPHS_ASCII)
+	default:
+		return nil, fmt.Errorf("theme: charset: unknown charset %q, expected one of auto, unicode, ascii: %w", theme.Charset, ErrInvalidOption)
+	}
+
+	for field, textStyle := range map[string]*TextStyle{"border_text": theme.BorderText, "header_text": theme.HeaderText, "footer_text": theme.FooterText} {
+		if textStyle == nil {
+			continue
+		}
+		if _, err := textStyle.sgr(); err != nil {
+			return nil, fmt.Errorf("theme: %s.%w", field, err)
+		}
+	}
+	if theme.BorderText != nil {
+		style.SetBorderTextStyle(*theme.BorderText)
+	}
+	if theme.HeaderText != nil {
+		style.SetHeaderTextStyle(*theme.HeaderText)
+	}
+	if theme.FooterText != nil {
+		style.SetFooterTextStyle(*theme.FooterText)
+	}
+
+	if err := style.Validate(); err != nil {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	return style, nil
+}
+
+// NewBorderStyleFromJSON creates border style from JSON theme. Unknown fields are errors.
+func NewBorderStyleFromJSON(reader io.Reader) (*BorderStyle, error) {
+	var theme Theme
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&theme); err != nil {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	return NewBorderStyleFromTheme(theme)
+}
+
+// NewBorderStyleFromFile creates border style from the JSON theme file (.json).
+func NewBorderStyleFromFile(path string) (*BorderStyle, error) {
+	if strings.ToLower(filepath.Ext(path)) != ".json" {
+		return nil, fmt.Errorf("theme: %s: unknown format, expected .json file: %w", path, ErrInvalidOption)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	style, err := NewBorderStyleFromJSON(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return style, nil
+}
+
+// Theme returns serializable definition of the style.
+func (style *BorderStyle) Theme() Theme {
+	theme := Theme{WidthFull: style.widthFull}
+	borderVisible, gridVisible, headerVisible := style.outer.IS_VISIBLE, style.inner.IS_VISIBLE, style.inner.HEADER_IS_VISIBLE
+	theme.BorderVisible, theme.GridVisible, theme.HeaderVisible = &borderVisible, &gridVisible, &headerVisible
+	switch style.glyphMode {
+	case GLYPHS_UNICODE:
+		theme.Charset = "unicode"
+	case GLYPHS_ASCII:
+		theme.Charset = "ascii"
+	}
+	if style.borderText != (TextStyle{}) {
+		borderText := style.borderText
+		theme.BorderText = &borderText
+	}
+	if style.headerText != (TextStyle{}) {
+		headerText := style.headerText
+		theme.HeaderText = &headerText
+	}
+	if style.footerText != (TextStyle{}) {
+		footerText := style.footerText
+		theme.FooterText = &footerText
+	}
+
+	if style.outer.style == BORDER_CUSTOM {
+		theme.Outer = "custom"
+		theme.Glyphs = &ThemeGlyphs{Outer: style.customOuter, Inner: style.customInner}
+		return theme
+	}
+
+	// Unknown outer styles are drawn in ASCII, unknown inner ones are thin
+	theme.Outer = themeLineName(style.outer.style, "ascii")
+	if theme.Outer == "none" {
+		theme.Outer = "ascii"
+	}
+	theme.Inner = themeLineName(style.inner.style, "thin")
+	if theme.Outer == "ascii" {
+		theme.Inner = "ascii"
+	} else if theme.Inner == "none" {
+		theme.Inner = "thin"
+	}
+	if theme.Inner == theme.Outer {
+		theme.Inner = ""
+	}
+	if style.header != BORDER_NONE {
+		theme.Header = themeLineName(style.header, "")
+	}
+	if style.footer != BORDER_NONE {
+		theme.Footer = themeLineName(style.footer, "")
+	}
+	if style.rowSeparator > -1 {
+		theme.Rows = themeLineName(style.rowSeparator, "")
+	}
+	if len(style.separators) > 0 {
+		theme.Columns = make(map[int]string)
+		for column, separator := range style.separators {
+			theme.Columns[column] = themeLineName(separator, "")
+		}
+	}
+	if len(style.groups) > 0 {
+		theme.HeaderGroups = make(map[int]string)
+		for level, separator := range style.groups {
+			theme.HeaderGroups[level] = themeLineName(separator, "")
+		}
+	}
+
+	return theme
+}
+
+// WriteJSON writes the style as JSON theme to the writer.
+func (style *BorderStyle) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(style.Theme())
+}
@@ -0,0 +1,62 @@
+package asciitable
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThemeRoundTrip(t *testing.T) {
+	styles := map[string]*BorderStyle{
+		"lines": NewBorderStyle(BORDER_SINGLE_THICK, BORDER_SINGLE_THIN).SetHeaderStyle(BORDER_DOUBLE).
+			SetFooterStyle(BORDER_SINGLE_THIN).SetRowSeparator(BORDER_NONE).SetColSeparator(BORDER_DASHED, 1).
+			SetHeaderGroupStyle(BORDER_DOUBLE, 0),
+		"colors": NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).
+			SetBorderTextStyle(TextStyle{Foreground: "bright-black"}).
+			SetHeaderTextStyle(TextStyle{Foreground: "cyan", Bold: true}).
+			SetFooterTextStyle(TextStyle{Foreground: "#ff8800", Background: "236", Underline: true}),
+		"ascii": NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_ASCII).SetBorderVisible(false),
+	}
+	for name, style := range styles {
+		t.Run(name, func(t *testing.T) {
+			var theme strings.Builder
+			if err := style.WriteJSON(&theme); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := NewBorderStyleFromJSON(strings.NewReader(theme.String()))
+			if err != nil {
+				t.Fatalf("%v, theme:\n%s", err, theme.String())
+			}
+			if !reflect.DeepEqual(loaded.Theme(), style.Theme()) {
+				t.Errorf("theme = %+v, expected %+v", loaded.Theme(), style.Theme())
+			}
+		})
+	}
+}
+
+func TestThemeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme string
+		field string
+	}{
+		{"unknown style", `{"outer": "wavy"}`, "outer"},
+		{"unknown field", `{"outer": "thin", "shadow": true}`, "shadow"},
+		{"unknown color", `{"outer": "thin", "header_text": {"fg": "plaid"}}`, "header_text.fg"},
+		{"columns of custom", `{"outer": "custom", "columns": {"0": "thin"}}`, "glyphs"},
+		{"header groups of custom", `{"glyphs": {"outer": {}, "inner": {}}, "header_groups": {"0": "thin"}}`, "header_groups"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewBorderStyleFromJSON(strings.NewReader(test.theme))
+			if err == nil || !strings.Contains(err.Error(), test.field) {
+				t.Errorf("error = %v, expected error of %s", err, test.field)
+			}
+		})
+	}
+	if _, err := NewBorderStyleFromFile("theme.yaml"); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("NewBorderStyleFromFile error = %v, expected %v", err, ErrInvalidOption)
+	}
+}
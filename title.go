@@ -0,0 +1,137 @@
+package asciitable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position of the table title.
+const (
+	TITLE_ABOVE  = iota // Centered line above the top border
+	TITLE_BORDER        // Embedded into the top border, e.g. "┌─ Disks ───┐"
+)
+
+/*
+Set title of the table, rendered centered above the table. Long title
+is wrapped or truncated with the same rules as cells. Empty title removes it.
+*/
+func (table *SimpleTable) SetTitle(title string) *SimpleTable {
+	table.title = title
+	return table
+}
+
+/*
+Set title position: TITLE_ABOVE (default) or TITLE_BORDER, which embeds
+the title into the top border. Title goes above the table anyway,
+if there is no top border or it is too short for the title.
+*/
+func (table *SimpleTable) SetTitlePosition(position int) *SimpleTable {
+	if position != TITLE_ABOVE && position != TITLE_BORDER {
+		table.setError(fmt.Errorf("SetTitlePosition: unknown position %d: %w", position, ErrInvalidOption))
+		return table
+	}
+	table.titlePosition = position
+	return table
+}
+
+/*
+Set caption or note of the table, rendered below the table. Long caption
+is wrapped or truncated with the same rules as cells. Empty caption removes it.
+*/
+func (table *SimpleTable) SetCaption(caption string) *SimpleTable {
+	table.caption = caption
+	return table
+}
+
+// Get width of the rendered table in terminal cells
+func (table *SimpleTable) tableWidth() int {
+	rowWidths := table.getRowWidths()
+	width := table.measure.width(table.style.outer.VerticalLine()) * 2
+	for idx, rowWidth := range rowWidths {
+		width += rowWidth
+		if idx < len(rowWidths)-1 {
+			width += table.measure.width(table.style.separator(idx).VerticalLine())
+		}
+	}
+	return width
+}
+
+// Get room for the title in the top border: corner and a line on both sides,
+// a space on both sides of the title. Zero means the title does not go into the border.
+func (table *SimpleTable) titleBorderWidth() int {
+	if table.title == "" || table.titlePosition != TITLE_BORDER ||
+		len(*table.Data().GetHeader()) == 0 || table.style.outer.HorisontalLine() == "" {
+		return 0
+	}
+	if width := table.tableWidth() - 6; width > 0 {
+		return width
+	}
+	return 0
+}
+
+// Render title or caption lines across the table width. Title is centered.
+func (table *SimpleTable) renderSpanning(data string, center bool) string {
+	if !table.colors {
+		data = table.stripAnsi(data)
+	}
+
+	width := table.tableWidth()
+	var content []string
+	if table.wrapText {
+		content = table.wrapCellData(data, width)
+	} else {
+		content = []string{table.truncateAnsi(data, width)}
+	}
+
+	lines := make([]string, len(content))
+	for idx, line := range content {
+		line = strings.TrimSpace(line)
+		if pad := (width - table.textWidth(line)) / 2; center && pad > 0 {
+			line = strings.Repeat(" ", pad) + line
+		}
+		lines[idx] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+/*
+Embed title into the top border. Glyphs may take more than one cell, e.g. box
+drawing ones with AMBIGUOUS_WIDE, so the border is walked by cells: the corner
+and a line are kept on both sides, the glyphs between them are replaced by
+the title, which is padded, if it ends in the middle of a glyph.
+*/
+func (table *SimpleTable) embedTitle(border string, width int) string {
+	glyphs := make([]string, 0, len(border))
+	for rest := border; len(rest) > 0; {
+		size := table.measure.nextCluster(rest)
+		glyphs = append(glyphs, rest[:size])
+		rest = rest[size:]
+	}
+	if len(glyphs) < 4 {
+		return border
+	}
+
+	// Room between the corner and a line on both sides, less a space around the title
+	last := len(glyphs) - 2
+	room := table.textWidth(border) - table.textWidth(strings.Join(glyphs[:2], "")) -
+		table.textWidth(strings.Join(glyphs[last:], "")) - 2
+	if room < width {
+		width = room
+	}
+	if width <= 0 {
+		return border
+	}
+
+	title := table.title
+	if !table.colors {
+		title = table.stripAnsi(title)
+	}
+	title = " " + table.truncateAnsi(strings.TrimSpace(title), width) + " "
+
+	cells, end := 0, 2
+	for titleWidth := table.textWidth(title); cells < titleWidth && end < last; end++ {
+		cells += table.textWidth(glyphs[end])
+	}
+	return strings.Join(glyphs[:2], "") + title + strings.Repeat(" ", cells-table.textWidth(title)) +
+		strings.Join(glyphs[end:], "")
+}
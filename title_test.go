@@ -0,0 +1,41 @@
+package asciitable
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTitleBorder(t *testing.T) {
+	rows := "\n│Hostname│Size│\n│alpha   │1   │\n└────────┴────┘"
+	tests := []struct {
+		name      string
+		title     string
+		ambiguous int
+		expected  string
+	}{
+		{"title", "Disks", AMBIGUOUS_NARROW, "\n┌─ Disks ┬────┐" + rows},
+		{"truncated", "Disks of the hosts", AMBIGUOUS_NARROW, "\n┌─ Disks ... ─┐" + rows},
+		{"wide character at the end", "Disks 日", AMBIGUOUS_NARROW, "\n┌─ Disks 日 ──┐" + rows},
+		{"wide character truncated", "Disks 日本", AMBIGUOUS_NARROW, "\n┌─ Disks ... ─┐" + rows},
+		// Box drawing glyphs take two cells, so the title is padded to the end of the glyph
+		{"ambiguous wide", "Disks", AMBIGUOUS_WIDE, "\n┌─ Disks  ───┬────┐" + rows},
+		{"ambiguous wide truncated", "Disks of the hosts", AMBIGUOUS_WIDE, "\n┌─ Disks of ... ┬────┐" + rows},
+		{"ambiguous wide with wide character at the end", "Disks 日", AMBIGUOUS_WIDE, "\n┌─ Disks 日 ──┬────┐" + rows},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			style := NewBorderStyle(BORDER_SINGLE_THIN, BORDER_SINGLE_THIN).SetGlyphMode(GLYPHS_UNICODE)
+			table := NewSimpleTable(NewTableData().SetHeader("Hostname", "Size").AddRow("alpha", 1), style).
+				SetAmbiguousWidth(test.ambiguous).SetTitle(test.title).SetTitlePosition(TITLE_BORDER)
+			rendered := table.Render()
+			if rendered != test.expected {
+				t.Errorf("Render() = %q, expected %q", rendered, test.expected)
+			}
+
+			lines := strings.Split(rendered, "\n")
+			if top, bottom := table.textWidth(lines[1]), table.textWidth(lines[len(lines)-1]); top != bottom {
+				t.Errorf("top border takes %d cells, expected %d", top, bottom)
+			}
+		})
+	}
+}
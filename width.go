@@ -0,0 +1,220 @@
+package asciitable
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// East Asian Ambiguous characters width policy.
+// Ambiguous characters are narrow in western locales, but wide in CJK ones.
+const (
+	AMBIGUOUS_NARROW = 1
+	AMBIGUOUS_WIDE   = 2
+)
+
+// Range of runes, inclusive
+type runeRange struct {
+	lo rune
+	hi rune
+}
+
+// East Asian Wide (W) and Fullwidth (F) characters, including emoji
+// with default emoji presentation.
+var _wideRunes = []runeRange{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18cff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f202}, {0x1f210, 0x1f23b},
+	{0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// East Asian Ambiguous (A) characters
+var _ambiguousRunes = []runeRange{
+	{0x00a1, 0x00a1}, {0x00a4, 0x00a4}, {0x00a7, 0x00a8}, {0x00aa, 0x00aa},
+	{0x00ae, 0x00ae}, {0x00b0, 0x00b4}, {0x00b6, 0x00ba}, {0x00bc, 0x00bf},
+	{0x00c6, 0x00c6}, {0x00d0, 0x00d0}, {0x00d7, 0x00d8}, {0x00de, 0x00e1},
+	{0x00e6, 0x00e6}, {0x00e8, 0x00ea}, {0x00ec, 0x00ed}, {0x00f0, 0x00f0},
+	{0x00f2, 0x00f3}, {0x00f7, 0x00fa}, {0x00fc, 0x00fc}, {0x00fe, 0x00fe},
+	{0x0101, 0x0101}, {0x0111, 0x0111}, {0x0113, 0x0113}, {0x011b, 0x011b},
+	{0x0126, 0x0127}, {0x012b, 0x012b}, {0x0131, 0x0133}, {0x0138, 0x0138},
+	{0x013f, 0x0142}, {0x0144, 0x0144}, {0x0148, 0x014b}, {0x014d, 0x014d},
+	{0x0152, 0x0153}, {0x0166, 0x0167}, {0x016b, 0x016b}, {0x01ce, 0x01ce},
+	{0x01d0, 0x01d0}, {0x01d2, 0x01d2}, {0x01d4, 0x01d4}, {0x01d6, 0x01d6},
+	{0x01d8, 0x01d8}, {0x01da, 0x01da}, {0x01dc, 0x01dc}, {0x0251, 0x0251},
+	{0x0261, 0x0261}, {0x02c4, 0x02c4}, {0x02c7, 0x02c7}, {0x02c9, 0x02cb},
+	{0x02cd, 0x02cd}, {0x02d0, 0x02d0}, {0x02d8, 0x02db}, {0x02dd, 0x02dd},
+	{0x02df, 0x02df}, {0x0391, 0x03a9}, {0x03b1, 0x03c9}, {0x0401, 0x0401},
+	{0x0410, 0x044f}, {0x0451, 0x0451}, {0x2010, 0x2010}, {0x2013, 0x2016},
+	{0x2018, 0x2019}, {0x201c, 0x201d}, {0x2020, 0x2022}, {0x2024, 0x2027},
+	{0x2030, 0x2030}, {0x2032, 0x2033}, {0x2035, 0x2035}, {0x203b, 0x203b},
+	{0x203e, 0x203e}, {0x2074, 0x2074}, {0x207f, 0x207f}, {0x2081, 0x2084},
+	{0x20ac, 0x20ac}, {0x2103, 0x2103}, {0x2105, 0x2105}, {0x2109, 0x2109},
+	{0x2113, 0x2113}, {0x2116, 0x2116}, {0x2121, 0x2122}, {0x2126, 0x2126},
+	{0x212b, 0x212b}, {0x2153, 0x2154}, {0x215b, 0x215e}, {0x2160, 0x216b},
+	{0x2170, 0x2179}, {0x2189, 0x2189}, {0x2190, 0x2199}, {0x21b8, 0x21b9},
+	{0x21d2, 0x21d2}, {0x21d4, 0x21d4}, {0x21e7, 0x21e7}, {0x2200, 0x2200},
+	{0x2202, 0x2203}, {0x2207, 0x2208}, {0x220b, 0x220b}, {0x220f, 0x220f},
+	{0x2211, 0x2211}, {0x2215, 0x2215}, {0x221a, 0x221a}, {0x221d, 0x2220},
+	{0x2223, 0x2223}, {0x2225, 0x2225}, {0x2227, 0x222c}, {0x222e, 0x222e},
+	{0x2234, 0x2237}, {0x223c, 0x223d}, {0x2248, 0x2248}, {0x224c, 0x224c},
+	{0x2252, 0x2252}, {0x2260, 0x2261}, {0x2264, 0x2267}, {0x226a, 0x226b},
+	{0x226e, 0x226f}, {0x2282, 0x2283}, {0x2286, 0x2287}, {0x2295, 0x2295},
+	{0x2299, 0x2299}, {0x22a5, 0x22a5}, {0x22bf, 0x22bf}, {0x2312, 0x2312},
+	{0x2460, 0x24e9}, {0x24eb, 0x254b}, {0x2550, 0x2573}, {0x2580, 0x258f},
+	{0x2592, 0x2595}, {0x25a0, 0x25a1}, {0x25a3, 0x25a9}, {0x25b2, 0x25b3},
+	{0x25b6, 0x25b7}, {0x25bc, 0x25bd}, {0x25c0, 0x25c1}, {0x25c6, 0x25c8},
+	{0x25cb, 0x25cb}, {0x25ce, 0x25d1}, {0x25e2, 0x25e5}, {0x25ef, 0x25ef},
+	{0x2605, 0x2606}, {0x2609, 0x2609}, {0x260e, 0x260f}, {0x261c, 0x261c},
+	{0x261e, 0x261e}, {0x2640, 0x2640}, {0x2642, 0x2642}, {0x2660, 0x2661},
+	{0x2663, 0x2665}, {0x2667, 0x266a}, {0x266c, 0x266d}, {0x266f, 0x266f},
+	{0x269e, 0x269f}, {0x26bf, 0x26bf}, {0x26c6, 0x26cd}, {0x26cf, 0x26d3},
+	{0x26d5, 0x26e1}, {0x26e3, 0x26e3}, {0x26e8, 0x26e9}, {0x26eb, 0x26f1},
+	{0x26f4, 0x26f4}, {0x26f6, 0x26f9}, {0x26fb, 0x26fc}, {0x26fe, 0x26ff},
+	{0x273d, 0x273d}, {0x2776, 0x277f}, {0x2b56, 0x2b59}, {0x3248, 0x324f},
+	{0xe000, 0xf8ff}, {0xfffd, 0xfffd}, {0x1f100, 0x1f10a}, {0x1f110, 0x1f12d},
+	{0x1f130, 0x1f169}, {0x1f170, 0x1f18d}, {0x1f18f, 0x1f190}, {0x1f19b, 0x1f1ac},
+	{0xf0000, 0xffffd}, {0x100000, 0x10fffd},
+}
+
+// Characters, which are always invisible and glued to the previous one:
+// Hangul medial vowels and final consonants.
+var _zeroWidthRunes = []runeRange{
+	{0x1160, 0x11ff}, {0xd7b0, 0xd7ff},
+}
+
+// Grapheme cluster forming characters
+const (
+	_zeroWidthJoiner = 0x200d
+	_variationLo     = 0xfe00
+	_variationEmoji  = 0xfe0f
+	_regionalLo      = 0x1f1e6
+	_regionalHi      = 0x1f1ff
+	_emojiModifierLo = 0x1f3fb
+	_emojiModifierHi = 0x1f3ff
+	_emojiTagLo      = 0xe0020
+	_emojiTagHi      = 0xe007f
+	_variationSupLo  = 0xe0100
+	_variationSupHi  = 0xe01ef
+)
+
+// Lookup rune in the sorted ranges table
+func inRuneRanges(r rune, table []runeRange) bool {
+	idx := sort.Search(len(table), func(i int) bool { return table[i].hi >= r })
+	return idx < len(table) && table[idx].lo <= r
+}
+
+// Display width engine. Measures text in terminal cells, rather than in bytes.
+type displayWidth struct {
+	ambiguous int
+}
+
+func newDisplayWidth() *displayWidth {
+	return &displayWidth{ambiguous: AMBIGUOUS_NARROW}
+}
+
+// Width of a single rune in terminal cells
+func (dw *displayWidth) runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x7f: // Plain ASCII, the most common case
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) || inRuneRanges(r, _zeroWidthRunes):
+		return 0
+	case inRuneRanges(r, _wideRunes):
+		return 2
+	case inRuneRanges(r, _ambiguousRunes):
+		return dw.ambiguous
+	}
+	return 1
+}
+
+// Tells if the rune extends the previous grapheme cluster, instead of starting a new one.
+func (dw *displayWidth) isExtending(r rune) bool {
+	return r == _zeroWidthJoiner ||
+		(r >= _variationLo && r <= _variationEmoji) ||
+		(r >= _emojiModifierLo && r <= _emojiModifierHi) ||
+		(r >= _emojiTagLo && r <= _emojiTagHi) ||
+		(r >= _variationSupLo && r <= _variationSupHi) ||
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		inRuneRanges(r, _zeroWidthRunes)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= _regionalLo && r <= _regionalHi
+}
+
+// Get length in bytes of the first grapheme cluster in the string.
+// Combining marks, emoji modifiers, variation selectors, regional indicator
+// pairs (flags) and zero-width joined sequences are kept together.
+func (dw *displayWidth) nextCluster(data string) int {
+	if data == "" {
+		return 0
+	}
+	if strings.HasPrefix(data, "\r\n") {
+		return 2
+	}
+
+	prev, offset := utf8.DecodeRuneInString(data)
+	regional := isRegionalIndicator(prev)
+	for offset < len(data) {
+		r, size := utf8.DecodeRuneInString(data[offset:])
+		switch {
+		case regional && isRegionalIndicator(r):
+			regional = false // Flag is exactly a pair of indicators
+		case prev == _zeroWidthJoiner || dw.isExtending(r):
+			regional = false
+		default:
+			return offset
+		}
+		offset += size
+		prev = r
+	}
+
+	return offset
+}
+
+// Width of a grapheme cluster, which is the width of its base character.
+// Emoji presentation selector makes narrow symbols wide.
+func (dw *displayWidth) clusterWidth(cluster string) int {
+	base, size := utf8.DecodeRuneInString(cluster)
+	width := dw.runeWidth(base)
+	if isRegionalIndicator(base) {
+		return 2
+	}
+	if width == 1 && strings.ContainsRune(cluster[size:], _variationEmoji) {
+		width = 2
+	}
+	return width
+}
+
+// Width of the plain text (no ANSI sequences) in terminal cells
+func (dw *displayWidth) width(data string) int {
+	width := 0
+	for len(data) > 0 {
+		size := dw.nextCluster(data)
+		width += dw.clusterWidth(data[:size])
+		data = data[size:]
+	}
+	return width
+}
@@ -0,0 +1,80 @@
+package asciitable
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		ambiguous int
+		width     int
+	}{
+		{"empty", "", AMBIGUOUS_NARROW, 0},
+		{"ascii", "Strawberry", AMBIGUOUS_NARROW, 10},
+		{"control", "a\tb\x7f", AMBIGUOUS_NARROW, 2},
+		{"umlauts", "Grüße", AMBIGUOUS_NARROW, 5},
+		{"decomposed umlaut", "u\u0308ber", AMBIGUOUS_NARROW, 4},
+		{"japanese", "日本語", AMBIGUOUS_NARROW, 6},
+		{"hangul jamo", "\u1100\u1161\u11a8", AMBIGUOUS_NARROW, 2},
+		{"fullwidth", "ＡＢ", AMBIGUOUS_NARROW, 4},
+		{"emoji", "✅ ok", AMBIGUOUS_NARROW, 5},
+		{"emoji presentation", "❤️", AMBIGUOUS_NARROW, 2},
+		{"skin tone", "👍🏽", AMBIGUOUS_NARROW, 2},
+		{"zwj family", "👨‍👩‍👧", AMBIGUOUS_NARROW, 2},
+		{"flag", "🇩🇪", AMBIGUOUS_NARROW, 2},
+		{"ambiguous narrow", "±°", AMBIGUOUS_NARROW, 2},
+		{"ambiguous wide", "±°", AMBIGUOUS_WIDE, 4},
+		{"cyrillic wide", "Да", AMBIGUOUS_WIDE, 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			measure := newDisplayWidth()
+			measure.ambiguous = test.ambiguous
+			if width := measure.width(test.data); width != test.width {
+				t.Errorf("width(%q) = %d, expected %d", test.data, width, test.width)
+			}
+		})
+	}
+}
+
+func TestNextCluster(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		clusters []string
+	}{
+		{"empty", "", nil},
+		{"ascii", "ab", []string{"a", "b"}},
+		{"crlf", "\r\nx", []string{"\r\n", "x"}},
+		{"combining", "e\u0301x", []string{"e\u0301", "x"}},
+		{"skin tone", "👍🏽!", []string{"👍🏽", "!"}},
+		{"zwj", "👨‍👩x", []string{"👨‍👩", "x"}},
+		{"flags", "🇩🇪🇫🇷", []string{"🇩🇪", "🇫🇷"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			measure := newDisplayWidth()
+			var clusters []string
+			for data := test.data; len(data) > 0; {
+				size := measure.nextCluster(data)
+				clusters = append(clusters, data[:size])
+				data = data[size:]
+			}
+			if len(clusters) != len(test.clusters) {
+				t.Fatalf("clusters of %q = %q, expected %q", test.data, clusters, test.clusters)
+			}
+			for idx := range clusters {
+				if clusters[idx] != test.clusters[idx] {
+					t.Errorf("clusters of %q = %q, expected %q", test.data, clusters, test.clusters)
+				}
+			}
+		})
+	}
+}
+
+func TestTextWidthSkipsAnsi(t *testing.T) {
+	table := NewSimpleTable(nil, nil)
+	if width := table.textWidth("\x1b[31;1m日本\x1b[0m ok"); width != 7 {
+		t.Errorf("textWidth = %d, expected 7", width)
+	}
+}